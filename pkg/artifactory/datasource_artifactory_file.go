@@ -1,6 +1,7 @@
 package artifactory
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
@@ -8,8 +9,7 @@ import (
 	"os"
 	"path/filepath"
 
-	"github.com/go-resty/resty/v2"
-
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -36,7 +36,7 @@ type Checksums struct {
 
 func dataSourceArtifactoryFile() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceFileRead,
+		ReadContext: dataSourceFileRead,
 
 		Schema: map[string]*schema.Schema{
 			"repository": {
@@ -104,15 +104,15 @@ func dataSourceArtifactoryFile() *schema.Resource {
 	}
 }
 
-func dataSourceFileRead(d *schema.ResourceData, m interface{}) error {
+func dataSourceFileRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	repository := d.Get("repository").(string)
 	path := d.Get("path").(string)
 	outputPath := d.Get("output_path").(string)
 	forceOverwrite := d.Get("force_overwrite").(bool)
 	fileInfo := FileInfo{}
-	_, err := m.(*resty.Client).R().SetResult(&fileInfo).Get(fmt.Sprintf("artifactory/api/storage/%s/%s", repository, path))
+	_, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).SetResult(&fileInfo).Get(fmt.Sprintf("artifactory/api/storage/%s/%s", repository, path))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	fileExists := FileExists(outputPath)
@@ -120,17 +120,17 @@ func dataSourceFileRead(d *schema.ResourceData, m interface{}) error {
 
 	if fileExists {
 		if !chksMatches && !forceOverwrite {
-			return fmt.Errorf("local file differs from upstream version and no overwrite is permitted")
+			return diag.Errorf("local file differs from upstream version and no overwrite is permitted")
 		}
 	} else {
 		outdir := filepath.Dir(outputPath)
 		err = os.MkdirAll(outdir, os.ModePerm)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		outFile, err := os.Create(outputPath)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
 		defer func(outFile *os.File) {
@@ -138,16 +138,16 @@ func dataSourceFileRead(d *schema.ResourceData, m interface{}) error {
 		}(outFile)
 	}
 
-	_, err = m.(*resty.Client).R().SetOutput(outputPath).Get(fileInfo.DownloadUri)
+	_, err = m.(*ProviderMetadata).Client.R().SetContext(ctx).SetOutput(outputPath).Get(fileInfo.DownloadUri)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	chksMatches, _ = VerifySha256Checksum(outputPath, fileInfo.Checksums.Sha256)
 	if !chksMatches {
-		return fmt.Errorf("%s checksum and %s checksum do not match, expectd %s", outputPath, fileInfo.DownloadUri, fileInfo.Checksums.Sha256)
+		return diag.Errorf("%s checksum and %s checksum do not match, expectd %s", outputPath, fileInfo.DownloadUri, fileInfo.Checksums.Sha256)
 	}
 
-	return packFileInfo(fileInfo, d)
+	return diag.FromErr(packFileInfo(fileInfo, d))
 }
 
 func FileExists(path string) bool {
@@ -1,16 +1,16 @@
 package artifactory
 
 import (
+	"context"
 	"fmt"
 
-	"github.com/go-resty/resty/v2"
-
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceArtifactoryFileInfo() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceFileInfoRead,
+		ReadContext: dataSourceFileInfoRead,
 
 		Schema: map[string]*schema.Schema{
 			"repository": {
@@ -70,17 +70,17 @@ func dataSourceArtifactoryFileInfo() *schema.Resource {
 	}
 }
 
-func dataSourceFileInfoRead(d *schema.ResourceData, m interface{}) error {
+func dataSourceFileInfoRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	repository := d.Get("repository").(string)
 	path := d.Get("path").(string)
 
 	fileInfo := FileInfo{}
-	_, err := m.(*resty.Client).R().SetResult(&fileInfo).Get(fmt.Sprintf("artifactory/api/storage/%s/%s", repository, path))
+	_, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).SetResult(&fileInfo).Get(fmt.Sprintf("artifactory/api/storage/%s/%s", repository, path))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	return packFileInfo(fileInfo, d)
+	return diag.FromErr(packFileInfo(fileInfo, d))
 }
 
 func packFileInfo(fileInfo FileInfo, d *schema.ResourceData) error {
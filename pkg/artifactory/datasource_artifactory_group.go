@@ -0,0 +1,27 @@
+package artifactory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceArtifactoryGroup() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceGroupRead,
+		Schema:      computedSchema(resourceArtifactoryGroup().Schema, "name", "detach_all_users"),
+	}
+}
+
+func dataSourceGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+	group := Group{}
+	url := fmt.Sprintf("%s%s?includeUsers=true", groupsEndpoint, name)
+	if _, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).SetResult(&group).Get(url); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(name)
+	return diag.FromErr(packGroup(&group, d))
+}
@@ -0,0 +1,12 @@
+package artifactory
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceArtifactoryGroups() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: mkNamesRead(groupsEndpoint),
+		Schema:      namesSchema,
+	}
+}
@@ -0,0 +1,26 @@
+package artifactory
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+)
+
+func dataSourceArtifactoryPermissionTarget() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourcePermissionTargetRead,
+		Schema:      computedSchema(resourceArtifactoryPermissionTarget().Schema, "name"),
+	}
+}
+
+func dataSourcePermissionTargetRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+	permissionTarget := new(services.PermissionTargetParams)
+	if _, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).SetResult(permissionTarget).Get(permissionsEndPoint + name); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(name)
+	return diag.FromErr(packPermissionTarget(permissionTarget, d))
+}
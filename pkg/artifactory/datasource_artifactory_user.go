@@ -0,0 +1,28 @@
+package artifactory
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+)
+
+func dataSourceArtifactoryUser() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceUserRead,
+		// artifactory never hands the password back, and generating and rotating it is up to the resource
+		Schema: computedSchema(resourceArtifactoryUser().Schema, "name", "password", "generate_password",
+			"password_policy", "rotation_days", "generated_password", "password_generated_at"),
+	}
+}
+
+func dataSourceUserRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+	user := services.User{}
+	if _, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).SetResult(&user).Get(usersEndpoint + name); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(name)
+	return diag.FromErr(packUser(user, d))
+}
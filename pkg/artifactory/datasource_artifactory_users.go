@@ -0,0 +1,63 @@
+package artifactory
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// namesSchema is the schema of the data sources listing the names of users and groups
+var namesSchema = map[string]*schema.Schema{
+	"name_regex": {
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validation.StringIsValidRegExp,
+		Description:  "Only list the names matching this regular expression. All of them if empty",
+	},
+	"names": {
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	},
+}
+
+func dataSourceArtifactoryUsers() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: mkNamesRead(usersEndpoint),
+		Schema:      namesSchema,
+	}
+}
+
+// mkNamesRead lists the names behind endpoint, which answers with [{"name": .., "uri": ..}] like the users and groups
+// apis do, and keeps the ones matching name_regex
+func mkNamesRead(endpoint string) schema.ReadContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		var filter *regexp.Regexp
+		if v, ok := d.GetOk("name_regex"); ok {
+			filter = regexp.MustCompile(v.(string))
+		}
+
+		var entries []struct {
+			Name string `json:"name"`
+		}
+		if _, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).SetResult(&entries).Get(endpoint); err != nil {
+			return diag.FromErr(err)
+		}
+		names := []string{}
+		for _, entry := range entries {
+			if filter == nil || filter.MatchString(entry.Name) {
+				names = append(names, entry.Name)
+			}
+		}
+		sort.Strings(names)
+
+		d.SetId(strconv.Itoa(schema.HashString(strings.Join(names, ","))))
+		return diag.FromErr(d.Set("names", names))
+	}
+}
@@ -0,0 +1,65 @@
+package artifactory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrincipalDataSources(t *testing.T) {
+	meta, client := newFakeMeta(t)
+	ctx := context.Background()
+
+	if _, err := client.R().SetBody(Group{Name: "team-a", Description: "team a"}).Put(groupsEndpoint + "team-a"); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"team-a-ci", "team-a-dev", "team-b-ci"} {
+		user := services.User{Name: name, Email: name + "@example.com", Password: "Passw0rd!"}
+		if name != "team-b-ci" {
+			user.Groups = []string{"team-a"}
+		}
+		if _, err := client.R().SetBody(user).Put(usersEndpoint + name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	permission := services.PermissionTargetParams{
+		Name: "team-a",
+		Repo: &services.PermissionTargetSection{
+			Repositories: []string{"ANY LOCAL"},
+			Actions:      &services.Actions{Groups: map[string][]string{"team-a": {"read", "write"}}},
+		},
+	}
+	if _, err := client.R().SetBody(permission).Post(permissionsEndPoint + "team-a"); err != nil {
+		t.Fatal(err)
+	}
+
+	read := func(res *schema.Resource, config map[string]interface{}) *schema.ResourceData {
+		d := schema.TestResourceDataRaw(t, res.Schema, config)
+		if diags := res.ReadContext(ctx, d, meta); diags.HasError() {
+			t.Fatalf("read failed: %v", diags)
+		}
+		return d
+	}
+
+	users := read(dataSourceArtifactoryUsers(), map[string]interface{}{"name_regex": "^team-a-"})
+	assert.Equal(t, []interface{}{"team-a-ci", "team-a-dev"}, users.Get("names"))
+	groups := read(dataSourceArtifactoryGroups(), map[string]interface{}{})
+	assert.Equal(t, []interface{}{"team-a"}, groups.Get("names"))
+
+	user := read(dataSourceArtifactoryUser(), map[string]interface{}{"name": "team-a-ci"})
+	assert.Equal(t, "team-a-ci@example.com", user.Get("email"))
+	assert.True(t, user.Get("groups").(*schema.Set).Contains("team-a"))
+	for _, key := range []string{"password", "generate_password", "password_policy", "rotation_days", "generated_password", "password_generated_at"} {
+		assert.NotContains(t, dataSourceArtifactoryUser().Schema, key)
+	}
+
+	group := read(dataSourceArtifactoryGroup(), map[string]interface{}{"name": "team-a"})
+	assert.Equal(t, "team a", group.Get("description"))
+	assert.Equal(t, 2, group.Get("users_names").(*schema.Set).Len())
+
+	target := read(dataSourceArtifactoryPermissionTarget(), map[string]interface{}{"name": "team-a"})
+	assert.Equal(t, 1, target.Get("repo.0.actions.0.groups").(*schema.Set).Len())
+}
@@ -0,0 +1,109 @@
+package artifactory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// ApiError is what every response with a status >= 400 turns into. Artifactory, Xray and Access all
+// have their own ideas about what an error body looks like, so whatever messages could be found are
+// pulled out, and the raw body is kept for when none could.
+type ApiError struct {
+	StatusCode int
+	Method     string
+	URL        string
+	Messages   []string
+	Body       string
+}
+
+// artifactoryErrorBody covers {"errors":[{"status":404,"message":"..."}]} (artifactory and access)
+// as well as {"error":"..."} and {"message":"..."} (xray)
+type artifactoryErrorBody struct {
+	Errors []struct {
+		Status  int    `json:"status"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+func newApiError(response *resty.Response) *ApiError {
+	apiErr := &ApiError{
+		StatusCode: response.StatusCode(),
+		Body:       string(response.Body()[:]),
+	}
+	if response.Request != nil {
+		apiErr.Method = response.Request.Method
+		apiErr.URL = response.Request.URL
+	}
+
+	body := artifactoryErrorBody{}
+	if err := json.Unmarshal(response.Body(), &body); err == nil {
+		for _, e := range body.Errors {
+			if e.Message != "" {
+				apiErr.Messages = append(apiErr.Messages, e.Message)
+			}
+		}
+		if body.Error != "" {
+			apiErr.Messages = append(apiErr.Messages, body.Error)
+		}
+		if body.Message != "" {
+			apiErr.Messages = append(apiErr.Messages, body.Message)
+		}
+	}
+	return apiErr
+}
+
+func (e *ApiError) Error() string {
+	detail := strings.Join(e.Messages, "; ")
+	if detail == "" {
+		// the message ends up in the debug log and in diagnostics, the body may echo what was sent
+		detail = strings.TrimSpace(scrubBody([]byte(e.Body), ""))
+	}
+	if detail == "" {
+		detail = http.StatusText(e.StatusCode)
+	}
+	return fmt.Sprintf("%d %s %s: %s", e.StatusCode, e.Method, e.URL, detail)
+}
+
+// HasStatus reports if err is an ApiError with any of the given status codes
+func HasStatus(err error, statuses ...int) bool {
+	var apiErr *ApiError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, status := range statuses {
+		if apiErr.StatusCode == status {
+			return true
+		}
+	}
+	return false
+}
+
+func IsNotFound(err error) bool {
+	return HasStatus(err, http.StatusNotFound)
+}
+
+func IsConflict(err error) bool {
+	return HasStatus(err, http.StatusConflict)
+}
+
+func IsForbidden(err error) bool {
+	return HasStatus(err, http.StatusForbidden)
+}
+
+// errorContains is for the cases where artifactory uses the same status for several things and only the
+// message tells them apart (eg. 'Token not revocable' or 'Could not merge and save new descriptor')
+func errorContains(err error, substr string) bool {
+	var apiErr *ApiError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return strings.Contains(apiErr.Body, substr)
+}
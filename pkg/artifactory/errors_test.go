@@ -0,0 +1,45 @@
+package artifactory
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestApiErrorParsesArtifactoryAndXrayBodies(t *testing.T) {
+	bodies := map[string]string{
+		"/artifactory/api/repositories/foo": `{"errors":[{"status":404,"message":"Repository foo not found"}]}`,
+		"/xray/api/v2/watches/foo":          `{"error":"Watch foo was not found"}`,
+		"/artifactory/api/plain":            `Not a json body`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(bodies[r.URL.Path]))
+	}))
+	defer server.Close()
+
+	client, err := buildResty(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetRetryCount(0)
+
+	expected := map[string]string{
+		"/artifactory/api/repositories/foo": "Repository foo not found",
+		"/xray/api/v2/watches/foo":          "Watch foo was not found",
+		"/artifactory/api/plain":            "Not a json body",
+	}
+	for path, message := range expected {
+		_, err := client.R().Get(path)
+		if !IsNotFound(err) {
+			t.Errorf("expected a not found error for %s, got %v", path, err)
+		}
+		if IsConflict(err) {
+			t.Errorf("did not expect a conflict for %s", path)
+		}
+		if !strings.Contains(err.Error(), message) || !strings.Contains(err.Error(), "404 GET") {
+			t.Errorf("expected %q to contain the status, method and %q", err.Error(), message)
+		}
+	}
+}
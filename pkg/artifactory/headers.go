@@ -0,0 +1,121 @@
+package artifactory
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// headerRule is one request_header block: a X-JFrog-* header sent only on the requests it matches
+type headerRule struct {
+	name       string
+	value      string
+	methods    map[string]bool
+	pathPrefix string
+}
+
+// reservedHeaders are set by the provider itself; letting `headers` override them would silently change the auth
+// method or break every payload
+var reservedHeaders = map[string]bool{
+	"authorization":   true,
+	"x-jfrog-art-api": true,
+	"content-type":    true,
+}
+
+var requestHeaderSchema = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"name": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.StringMatch(regexp.MustCompile(`(?i)^X-JFrog-[A-Za-z0-9-]+$`), "must be a X-JFrog-* header"),
+		},
+		"value": {
+			Type:      schema.TypeString,
+			Required:  true,
+			Sensitive: true,
+		},
+		"methods": {
+			Type:     schema.TypeSet,
+			Optional: true,
+			Elem: &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringInSlice([]string{
+				http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+			}, false)},
+			Set:         schema.HashString,
+			Description: "Only send the header on these methods. All methods if empty",
+		},
+		"path_prefix": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Only send the header on requests whose path starts with this, eg `artifactory/api/repositories`. All paths if empty",
+		},
+	},
+}
+
+func validateHeaders(value interface{}, key string) (ws []string, es []error) {
+	for name := range value.(map[string]interface{}) {
+		if reservedHeaders[strings.ToLower(name)] {
+			es = append(es, fmt.Errorf("%s can't set %s, it is managed by the provider", key, name))
+		}
+	}
+	return
+}
+
+func unpackHeaderRules(blocks []interface{}) []headerRule {
+	var rules []headerRule
+	for _, block := range blocks {
+		b := block.(map[string]interface{})
+		rule := headerRule{
+			name:       b["name"].(string),
+			value:      b["value"].(string),
+			pathPrefix: strings.Trim(b["path_prefix"].(string), "/"),
+		}
+		if methods, ok := b["methods"].(*schema.Set); ok && methods.Len() > 0 {
+			rule.methods = map[string]bool{}
+			for _, method := range castToStringArr(methods.List()) {
+				rule.methods[method] = true
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+func (rule headerRule) matches(method, path string) bool {
+	if rule.methods != nil && !rule.methods[method] {
+		return false
+	}
+	return strings.HasPrefix(path, rule.pathPrefix)
+}
+
+// requestPath is the path of a request relative to the host. Resources mostly pass relative urls, but downloads use
+// the absolute one artifactory hands back
+func requestPath(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil {
+		return strings.TrimPrefix(u.Path, "/")
+	}
+	return strings.TrimPrefix(rawURL, "/")
+}
+
+// addHeadersToResty sends headers with every request and each rule's header with the requests it matches.
+// Rules win over headers for the same name, since they are the more specific of the two
+func addHeadersToResty(client *resty.Client, headers map[string]string, rules []headerRule) *resty.Client {
+	client.SetHeaders(headers)
+	if len(rules) == 0 {
+		return client
+	}
+	return client.OnBeforeRequest(func(_ *resty.Client, request *resty.Request) error {
+		path := requestPath(request.URL)
+		for _, rule := range rules {
+			if rule.matches(request.Method, path) {
+				request.SetHeader(rule.name, rule.value)
+			}
+		}
+		return nil
+	})
+}
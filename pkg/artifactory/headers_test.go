@@ -0,0 +1,69 @@
+package artifactory
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestProviderHeaders(t *testing.T) {
+	var mu sync.Mutex
+	seen := map[string]http.Header{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen[r.Method+" "+r.URL.Path] = r.Header.Clone()
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"version": "7.27.10"}`))
+	}))
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"url":          server.URL,
+		"access_token": "token",
+		"headers":      map[string]interface{}{"X-Edge-Route": "eu"},
+		"request_header": []interface{}{
+			map[string]interface{}{
+				"name":        "X-JFrog-Impersonate",
+				"value":       "project-admin",
+				"methods":     []interface{}{"PUT"},
+				"path_prefix": "/artifactory/api/repositories/",
+			},
+		},
+	})
+	meta, err := providerConfigure(d, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := meta.(*ProviderMetadata).Client
+	if _, err := client.R().Put("artifactory/api/repositories/foo"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.R().Get("artifactory/api/repositories/foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	usage := seen["POST /artifactory/api/system/usage"]
+	if usage.Get("X-Edge-Route") != "" || usage.Get("X-JFrog-Impersonate") != "" {
+		t.Errorf("custom headers leaked into the usage report: %v", usage)
+	}
+	if version := seen["GET /artifactory/api/system/version"]; version.Get("X-Edge-Route") != "eu" {
+		t.Errorf("expected headers on every other request, got %v", version)
+	}
+	if put := seen["PUT /artifactory/api/repositories/foo"]; put.Get("X-JFrog-Impersonate") != "project-admin" {
+		t.Errorf("expected the request header on a matching request, got %v", put)
+	}
+	if get := seen["GET /artifactory/api/repositories/foo"]; get.Get("X-JFrog-Impersonate") != "" || get.Get("X-Edge-Route") != "eu" {
+		t.Errorf("expected only the global headers on a GET, got %v", get)
+	}
+}
+
+func TestValidateHeaders(t *testing.T) {
+	_, errs := validateHeaders(map[string]interface{}{"Authorization": "Basic x", "X-Edge-Route": "eu"}, "headers")
+	if len(errs) != 1 {
+		t.Errorf("expected only Authorization to be rejected, got %v", errs)
+	}
+}
@@ -0,0 +1,234 @@
+package artifactory
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	redacted = "***"
+	// bodies beyond this are cut short in the log; nobody reads a 2MB storage listing in TF_LOG output
+	maxLoggedBody = 8 * 1024
+)
+
+// sensitiveKeys are compared after lower casing and dropping '_' and '-', so 'private_key', 'privateKey'
+// and 'X-JFrog-Art-Api' all match. Anything ending in password or secret is caught by isSensitiveKey as well
+// (managerPassword, clientSecret, the password of each replication, ...)
+var sensitiveKeys = map[string]bool{
+	"accesstoken":    true,
+	"refreshtoken":   true,
+	"referencetoken": true,
+	"token":          true,
+	"xjfrogartapi":   true,
+	"authorization":  true,
+	"apikey":         true,
+	"privatekey":     true,
+	"passphrase":     true,
+}
+
+func isSensitiveKey(key string) bool {
+	normalized := strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+	return sensitiveKeys[normalized] ||
+		strings.HasSuffix(normalized, "password") ||
+		strings.HasSuffix(normalized, "secret")
+}
+
+// addLoggingToResty registers the debug hooks. It has to be called before any hook that turns a response into
+// an error, since resty stops running OnAfterResponse hooks at the first one that fails.
+func addLoggingToResty(client *resty.Client) *resty.Client {
+	client.OnAfterResponse(func(_ *resty.Client, response *resty.Response) error {
+		if response != nil && logging.IsDebugOrHigher() {
+			logResponse(response)
+		}
+		return nil
+	})
+	client.OnError(func(request *resty.Request, err error) {
+		if logging.IsDebugOrHigher() {
+			log.Printf("[DEBUG] %s %s failed: %s", request.Method, request.URL, err)
+		}
+	})
+	return client
+}
+
+func logResponse(response *resty.Response) {
+	request := response.Request
+	message := &strings.Builder{}
+	fmt.Fprintf(message, "[DEBUG] %s %s -> %d (%s)", request.Method, requestURL(request), response.StatusCode(), response.Time())
+	if request.RawRequest != nil {
+		fmt.Fprintf(message, "\nRequest headers: %s", redactHeaders(request.RawRequest.Header))
+	}
+	if body := requestBody(request); body != "" {
+		fmt.Fprintf(message, "\nRequest body: %s", body)
+	}
+	if body := response.Body(); len(body) > 0 {
+		fmt.Fprintf(message, "\nResponse body: %s", redactBody(body, response.Header().Get("content-type")))
+	}
+	log.Print(message.String())
+}
+
+func requestURL(request *resty.Request) string {
+	if request.RawRequest != nil && request.RawRequest.URL != nil {
+		return request.RawRequest.URL.String()
+	}
+	return request.URL
+}
+
+func requestBody(request *resty.Request) string {
+	if len(request.FormData) > 0 {
+		return redactForm(request.FormData)
+	}
+	contentType := request.Header.Get("content-type")
+	switch body := request.Body.(type) {
+	case nil:
+		return ""
+	case string:
+		return redactBody([]byte(body), contentType)
+	case []byte:
+		return redactBody(body, contentType)
+	case io.Reader:
+		return "<stream>"
+	default:
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Sprintf("<unprintable %T>", body)
+		}
+		return redactBody(raw, "application/json")
+	}
+}
+
+func redactHeaders(header http.Header) string {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var parts []string
+	for _, name := range names {
+		value := strings.Join(header[name], ", ")
+		if isSensitiveKey(name) {
+			value = redacted
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", name, value))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func redactForm(values url.Values) string {
+	clean := url.Values{}
+	for key, vals := range values {
+		if isSensitiveKey(key) {
+			clean.Set(key, redacted)
+			continue
+		}
+		clean[key] = vals
+	}
+	return clean.Encode()
+}
+
+// redactBody is scrubBody cut down to a size that's reasonable for a log line
+func redactBody(body []byte, contentType string) string {
+	out := scrubBody(body, contentType)
+	if len(out) > maxLoggedBody {
+		out = fmt.Sprintf("%s... (%d bytes truncated)", out[:maxLoggedBody], len(out)-maxLoggedBody)
+	}
+	return out
+}
+
+// privateKeyBlock is a PEM private key, whatever its kind (RSA, EC, OPENSSH, ENCRYPTED, ...). artifactory_certificate
+// sends one as text/plain along with the certificate
+var privateKeyBlock = regexp.MustCompile(`(?s)-----BEGIN ([A-Z0-9 ]*)PRIVATE KEY-----.*?-----END [A-Z0-9 ]*PRIVATE KEY-----`)
+
+// xmlElement is an element with only text in it. Go's regexp has no backreferences, so redactXml checks the end tag
+// matches
+var xmlElement = regexp.MustCompile(`<([A-Za-z_][\w.:-]*)([^<>]*)>([^<]*)</([A-Za-z_][\w.:-]*)>`)
+
+// scrubBody rewrites a body with every sensitive value masked. JSON and YAML (the config descriptor patches) are
+// parsed and masked by key, XML (the config descriptor itself) by element name. PEM private keys are masked wherever
+// they are, including in bodies that are none of these
+func scrubBody(body []byte, contentType string) string {
+	trimmed := bytes.TrimSpace(body)
+	contentType = strings.ToLower(contentType)
+	var out string
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		var parsed interface{}
+		if err := json.Unmarshal(trimmed, &parsed); err == nil {
+			if raw, err := json.Marshal(redactValue(parsed)); err == nil {
+				out = string(raw)
+			}
+		}
+	}
+	if out == "" && strings.Contains(contentType, "yaml") {
+		var parsed interface{}
+		if err := yaml.Unmarshal(trimmed, &parsed); err == nil {
+			if raw, err := yaml.Marshal(redactValue(parsed)); err == nil {
+				out = string(raw)
+			}
+		}
+	}
+	if out == "" && (strings.Contains(contentType, "xml") || (len(trimmed) > 0 && trimmed[0] == '<')) {
+		out = redactXml(string(trimmed))
+	}
+	if out == "" {
+		out = string(trimmed)
+	}
+	return redactPrivateKeys(out)
+}
+
+func redactPrivateKeys(text string) string {
+	return privateKeyBlock.ReplaceAllString(text, "-----BEGIN ${1}PRIVATE KEY-----"+redacted+"-----END ${1}PRIVATE KEY-----")
+}
+
+func redactXml(text string) string {
+	return xmlElement.ReplaceAllStringFunc(text, func(element string) string {
+		parts := xmlElement.FindStringSubmatch(element)
+		name := parts[1]
+		if i := strings.LastIndex(name, ":"); i >= 0 {
+			name = name[i+1:]
+		}
+		if parts[1] != parts[4] || parts[3] == "" || !isSensitiveKey(name) {
+			return element
+		}
+		return fmt.Sprintf("<%s%s>%s</%s>", parts[1], parts[2], redacted, parts[4])
+	})
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if isSensitiveKey(key) && item != nil {
+				v[key] = redacted
+			} else {
+				v[key] = redactValue(item)
+			}
+		}
+	case map[interface{}]interface{}:
+		for key, item := range v {
+			if isSensitiveKey(fmt.Sprint(key)) && item != nil {
+				v[key] = redacted
+			} else {
+				v[key] = redactValue(item)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	case string:
+		return redactPrivateKeys(v)
+	}
+	return value
+}
@@ -2,8 +2,11 @@ package artifactory
 
 import (
 	"fmt"
+	"log"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/go-resty/resty/v2"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -59,6 +62,40 @@ func Provider() *schema.Provider {
 				ConflictsWith: []string{"api_key", "password"},
 				Description:   "This is a bearer token that can be given to you by your admin under `Identity and Access`",
 			},
+			"max_concurrent_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ARTIFACTORY_MAX_CONCURRENT_REQUESTS", 0),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum number of requests in flight at once, regardless of terraform's -parallelism. 0 means no limit",
+			},
+			"requests_per_second": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ARTIFACTORY_REQUESTS_PER_SECOND", 0),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum number of requests started per second. 0 means no limit",
+			},
+			"project_key": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("ARTIFACTORY_PROJECT_KEY", nil),
+				ValidateFunc: validateProjectKey,
+				Description:  "Project that repositories and xray policies and watches are put in when they don't set their own project_key",
+			},
+			"headers": {
+				Type:         schema.TypeMap,
+				Optional:     true,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				ValidateFunc: validateHeaders,
+				Description:  "Headers sent with every request, eg for edge nodes that route by header",
+			},
+			"request_header": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        requestHeaderSchema,
+				Description: "A X-JFrog-* header sent only with the requests matching its methods and path_prefix",
+			},
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
@@ -78,6 +115,7 @@ func Provider() *schema.Provider {
 			"artifactory_virtual_go_repository":      resourceArtifactoryGoVirtualRepository(),
 			"artifactory_group":                      resourceArtifactoryGroup(),
 			"artifactory_user":                       resourceArtifactoryUser(),
+			"artifactory_user_ssh_key":               resourceArtifactoryUserSshKey(),
 			"artifactory_permission_target":          resourceArtifactoryPermissionTarget(),
 			"artifactory_replication_config":         resourceArtifactoryReplicationConfig(),
 			"artifactory_single_replication_config":  resourceArtifactorySingleReplicationConfig(),
@@ -87,6 +125,17 @@ func Provider() *schema.Provider {
 			"artifactory_general_security":           resourceArtifactoryGeneralSecurity(),
 			"artifactory_oauth_settings":             resourceArtifactoryOauthSettings(),
 			"artifactory_saml_settings":              resourceArtifactorySamlSettings(),
+			"artifactory_saml_provider":              resourceArtifactorySamlProvider(),
+			"artifactory_ldap_setting":               resourceArtifactoryLdapSetting(),
+			"artifactory_ldap_group_setting":         resourceArtifactoryLdapGroupSetting(),
+			"artifactory_crowd_settings":             resourceArtifactoryCrowdSettings(),
+			"artifactory_http_sso_settings":          resourceArtifactoryHttpSsoSettings(),
+			"artifactory_ssh_server_settings":        resourceArtifactorySshServerSettings(),
+			"artifactory_project":                    resourceArtifactoryProject(),
+			"artifactory_project_repository":         resourceArtifactoryProjectRepository(),
+			"artifactory_group_members":              resourceArtifactoryGroupMembers(),
+			"artifactory_user_groups":                resourceArtifactoryUserGroups(),
+			"artifactory_scoped_token":               resourceArtifactoryScopedToken(),
 			// Deprecated. Remove in V3
 			"artifactory_permission_targets": resourceArtifactoryPermissionTargets(),
 			// Xray resources
@@ -95,8 +144,13 @@ func Provider() *schema.Provider {
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
-			"artifactory_file":     dataSourceArtifactoryFile(),
-			"artifactory_fileinfo": dataSourceArtifactoryFileInfo(),
+			"artifactory_file":              dataSourceArtifactoryFile(),
+			"artifactory_fileinfo":          dataSourceArtifactoryFileInfo(),
+			"artifactory_user":              dataSourceArtifactoryUser(),
+			"artifactory_users":             dataSourceArtifactoryUsers(),
+			"artifactory_group":             dataSourceArtifactoryGroup(),
+			"artifactory_groups":            dataSourceArtifactoryGroups(),
+			"artifactory_permission_target": dataSourceArtifactoryPermissionTarget(),
 		},
 	}
 
@@ -119,19 +173,23 @@ func buildResty(URL string) (*resty.Client, error) {
 		return nil, err
 	}
 	baseUrl := fmt.Sprintf("%s://%s", u.Scheme, u.Host)
-	restyBase := resty.New().SetHostURL(baseUrl).OnAfterResponse(func(client *resty.Client, response *resty.Response) error {
+	restyBase := addLoggingToResty(resty.New()).SetHostURL(baseUrl).OnAfterResponse(func(client *resty.Client, response *resty.Response) error {
 		if response == nil {
 			return fmt.Errorf("no response found")
 		}
 		if response.StatusCode() >= http.StatusBadRequest {
-			return fmt.Errorf("\n%d %s %s\n%s", response.StatusCode(), response.Request.Method, response.Request.URL, string(response.Body()[:]))
+			return newApiError(response)
 		}
 		return nil
 	}).
 		SetHeader("content-type", "application/json").
 		SetHeader("accept", "*/*").
 		SetHeader("user-agent", "jfrog/terraform-provider-artifactory:"+Version).
-		SetRetryCount(5)
+		SetRetryCount(5).
+		AddRetryCondition(retryOnTooManyRequests).
+		SetRetryAfter(retryAfter).
+		// resty caps retryAfter's result at this. retryAfter keeps other retries at the default 2s itself
+		SetRetryMaxWaitTime(retryAfterMaxWait)
 	restyBase.DisableWarn = true
 	return restyBase, nil
 
@@ -170,14 +228,136 @@ func providerConfigure(d *schema.ResourceData, terraformVersion string) (interfa
 	if err != nil {
 		return nil, err
 	}
+	restyBase = addThrottleToResty(restyBase, d.Get("max_concurrent_requests").(int), d.Get("requests_per_second").(int))
+
 	_, err = sendUsageRepo(restyBase, terraformVersion)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return restyBase, nil
+	// only added now, so none of them (impersonation in particular) ends up on the usage report
+	headers := map[string]string{}
+	for name, value := range d.Get("headers").(map[string]interface{}) {
+		headers[name] = value.(string)
+	}
+	restyBase = addHeadersToResty(restyBase, headers, unpackHeaderRules(d.Get("request_header").([]interface{})))
+
+	meta, err := newProviderMetadata(restyBase)
+	if err != nil {
+		return nil, err
+	}
+	meta.ProjectKey = d.Get("project_key").(string)
+	return meta, nil
+}
+
+// ProviderMetadata is what every resource receives as its meta. Besides the shared client, it records
+// what was learnt about the server at configure time, so resources can fail with a meaningful message
+// rather than whatever 404 or 400 artifactory chooses to send back for an unsupported feature
+type ProviderMetadata struct {
+	Client             *resty.Client
+	ArtifactoryVersion string
+	LicenseType        string
+	// ProjectKey is the provider's default project_key
+	ProjectKey string
+	// descriptorLock is held by anything that rewrites the global config descriptor. See lockDescriptor
+	descriptorLock ctxLock
+	// membershipLock is held while a user's groups are read and written back. See lockMembership
+	membershipLock ctxLock
+}
+
+type ArtifactoryVersion struct {
+	Version  string   `json:"version"`
+	Revision string   `json:"revision"`
+	Addons   []string `json:"addons"`
+	License  string   `json:"license"`
+}
+
+type ArtifactoryLicense struct {
+	Type         string `json:"type"`
+	ValidThrough string `json:"validThrough"`
+	LicensedTo   string `json:"licensedTo"`
+}
+
+func newProviderMetadata(client *resty.Client) (*ProviderMetadata, error) {
+	version := ArtifactoryVersion{}
+	_, err := client.R().SetResult(&version).Get("artifactory/api/system/version")
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine the artifactory version %s", err)
+	}
+
+	// the licenses endpoint is admin only, so not knowing the license is not fatal
+	license := ArtifactoryLicense{}
+	if _, err = client.R().SetResult(&license).Get("artifactory/api/system/licenses"); err != nil {
+		log.Printf("[WARN] unable to determine the artifactory license type: %s", err)
+	}
+
+	return &ProviderMetadata{
+		Client:             client,
+		ArtifactoryVersion: version.Version,
+		LicenseType:        license.Type,
+	}, nil
+}
+
+// requireVersion errors when the server is known to be older than min. An unknown version is let through
+func (m *ProviderMetadata) requireVersion(feature, min string) error {
+	if m.ArtifactoryVersion == "" || compareVersions(m.ArtifactoryVersion, min) >= 0 {
+		return nil
+	}
+	return fmt.Errorf("%s requires Artifactory >= %s, but the server is running %s", feature, min, m.ArtifactoryVersion)
+}
+
+// requireEnterprise errors when the license is known not to be an Enterprise (or Enterprise+/Edge) one.
+// An unknown license is let through, since only admins may read it
+func (m *ProviderMetadata) requireEnterprise(feature string) error {
+	if m.LicenseType == "" || isEnterpriseLicense(m.LicenseType) {
+		return nil
+	}
+	return fmt.Errorf("%s requires an Enterprise or Enterprise+ license, but the server is licensed as %q", feature, m.LicenseType)
+}
 
+// requireAccessToken errors unless the provider authenticates with an access token. The Access apis (projects,
+// scoped tokens) don't take basic auth or api keys
+func (m *ProviderMetadata) requireAccessToken(feature string) error {
+	if m.Client == nil || m.Client.Token != "" {
+		return nil
+	}
+	return fmt.Errorf("%s requires the provider to authenticate with access_token", feature)
+}
+
+func isEnterpriseLicense(licenseType string) bool {
+	lower := strings.ToLower(licenseType)
+	return strings.Contains(lower, "enterprise") || strings.Contains(lower, "edge")
+}
+
+// compareVersions compares dotted version strings numerically, returning -1, 0 or 1.
+// Anything after the numeric part of a segment (eg "-rc1") is ignored
+func compareVersions(a, b string) int {
+	left := strings.Split(a, ".")
+	right := strings.Split(b, ".")
+	for i := 0; i < len(left) || i < len(right); i++ {
+		l, r := versionSegment(left, i), versionSegment(right, i)
+		if l < r {
+			return -1
+		}
+		if l > r {
+			return 1
+		}
+	}
+	return 0
+}
+
+func versionSegment(segments []string, i int) int {
+	if i >= len(segments) {
+		return 0
+	}
+	digits := strings.TrimLeftFunc(segments[i], func(r rune) bool { return r < '0' || r > '9' })
+	end := strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' })
+	if end >= 0 {
+		digits = digits[:end]
+	}
+	n, _ := strconv.Atoi(digits)
+	return n
 }
 
 func sendUsageRepo(restyBase *resty.Client, terraformVersion string) (interface{}, error) {
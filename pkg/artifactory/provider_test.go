@@ -5,14 +5,46 @@ import (
 	"github.com/go-resty/resty/v2"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/jfrog/terraform-provider-artifactory/pkg/fakeartifactory"
 )
 
+// TestMain points the acceptance tests at an in-process fake when ARTIFACTORY_FAKE is set, so they can run without a
+// real instance. Anything the fake doesn't serve comes back as a 404 naming the route
+func TestMain(m *testing.M) {
+	if os.Getenv("ARTIFACTORY_FAKE") == "" {
+		os.Exit(m.Run())
+	}
+	server := fakeartifactory.NewServer()
+	os.Setenv("ARTIFACTORY_URL", server.URL)
+	if os.Getenv("ARTIFACTORY_USERNAME") == "" {
+		os.Setenv("ARTIFACTORY_USERNAME", "admin")
+		os.Setenv("ARTIFACTORY_PASSWORD", "password")
+	}
+	code := m.Run()
+	server.Close()
+	os.Exit(code)
+}
+
+// newFakeMeta is the provider metadata of a fake of its own, for tests that call the CRUD functions directly. The
+// fake is closed when the test ends
+func newFakeMeta(t *testing.T) (*ProviderMetadata, *resty.Client) {
+	t.Helper()
+	server := fakeartifactory.NewServer()
+	t.Cleanup(server.Close)
+	client, err := buildResty(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &ProviderMetadata{Client: client}, client
+}
+
 var testAccProviders = func() map[string]func() (*schema.Provider, error) {
-	provider := Provider()
+	provider := recordingProvider(Provider())
 	return map[string]func() (*schema.Provider, error){
 		"artifactory": func() (*schema.Provider, error) {
 			return provider, nil
@@ -58,6 +90,7 @@ func getTestResty(t *testing.T) *resty.Client {
 }
 
 func testAccPreCheck(t *testing.T) {
+	recordFixture(t, filepath.Join("acceptance", t.Name()))
 	// https://www.jfrog.com/confluence/display/JFROG/Artifactory+REST+API#ArtifactoryRESTAPI-UpdateCustomURLBase
 	restyClient := getTestResty(t)
 	// TODO check the payload and make sure it's the right license type
@@ -72,3 +105,70 @@ func testAccPreCheck(t *testing.T) {
 		t.Fatal(oldErr)
 	}
 }
+
+func TestCompareVersions(t *testing.T) {
+	cases := []struct {
+		left, right string
+		expected    int
+	}{
+		{"7.27.10", "7.27.10", 0},
+		{"7.27.10", "7.9.0", 1},
+		{"6.23.3", "7.0.0", -1},
+		{"7.21", "7.21.0", 0},
+		{"7.21.1-rc1", "7.21.1", 0},
+	}
+	for _, c := range cases {
+		if actual := compareVersions(c.left, c.right); actual != c.expected {
+			t.Errorf("compareVersions(%q, %q) expected %d but got %d", c.left, c.right, c.expected, actual)
+		}
+	}
+}
+
+func TestProviderMetadataRequirements(t *testing.T) {
+	meta := &ProviderMetadata{ArtifactoryVersion: "7.10.2", LicenseType: "Commercial"}
+	if err := meta.requireVersion("feature", "7.10.0"); err != nil {
+		t.Error(err)
+	}
+	if err := meta.requireVersion("feature", "7.21.1"); err == nil {
+		t.Error("expected an error for a server older than the minimum version")
+	}
+	if err := meta.requireEnterprise("feature"); err == nil {
+		t.Error("expected an error for a pro license")
+	}
+	for _, license := range []string{"Enterprise", "Enterprise Plus Trial", "Edge"} {
+		meta.LicenseType = license
+		if err := meta.requireEnterprise("feature"); err != nil {
+			t.Error(err)
+		}
+	}
+	unknown := &ProviderMetadata{}
+	if unknown.requireVersion("feature", "7.0.0") != nil || unknown.requireEnterprise("feature") != nil {
+		t.Error("an unknown version or license should not block anything")
+	}
+}
+
+// TestFakeServerLocalRepository drives the local repository resource through the fake without terraform, which keeps
+// the fake honest about the calls the provider actually makes
+func TestFakeServerLocalRepository(t *testing.T) {
+	meta, _ := newFakeMeta(t)
+	ctx := context.Background()
+
+	res := resourceArtifactoryLocalRepository()
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{
+		"key":          "fake-local",
+		"package_type": "generic",
+		"description":  "served by the fake",
+	})
+	if diags := res.CreateContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("create failed: %v", diags)
+	}
+	if d.Id() != "fake-local" || d.Get("description") != "served by the fake" {
+		t.Errorf("unexpected state after create: %s %v", d.Id(), d.Get("description"))
+	}
+	if diags := res.DeleteContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("delete failed: %v", diags)
+	}
+	if diags := res.ReadContext(ctx, d, meta); diags.HasError() || d.Id() != "" {
+		t.Errorf("expected the repository to be gone after delete, got %v %s", diags, d.Id())
+	}
+}
@@ -1,11 +1,13 @@
 package artifactory
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
-	"net/http"
 	"reflect"
 	"regexp"
 	"strings"
@@ -127,8 +129,6 @@ func (bp VirtualRepositoryBaseParams) Id() string {
 	return bp.Key
 }
 
-type ReadFunc func(d *schema.ResourceData, m interface{}) error
-
 // Constructor Must return a pointer to a struct. When just returning a struct, resty gets confused and thinks it's a map
 type Constructor func() interface{}
 
@@ -139,90 +139,207 @@ type PackFunc func(repo interface{}, d *schema.ResourceData) error
 
 var mergeAndSaveRegex = regexp.MustCompile(".*Could not merge and save new descriptor.*")
 var retryOnMergeError = func(response *resty.Response, _r error) bool {
-	return mergeAndSaveRegex.MatchString(string(response.Body()[:]))
+	return response != nil && mergeAndSaveRegex.MatchString(string(response.Body()[:]))
+}
+
+// repoProjectKeySchema is shared by every repository resource. See withProject
+var repoProjectKeySchema = &schema.Schema{
+	Type:         schema.TypeString,
+	Optional:     true,
+	Computed:     true,
+	ValidateFunc: validateProjectKey,
+	Description:  "Project the repository belongs to. Defaults to the provider's project_key when it's created. Removing it doesn't unassign the repository",
+}
+
+// repoProjectEnvironmentsSchema is where the repository shows up in the project, artifactory puts it in DEV when
+// none is given
+var repoProjectEnvironmentsSchema = &schema.Schema{
+	Type:     schema.TypeSet,
+	Optional: true,
+	Computed: true,
+	MaxItems: 2,
+	Elem: &schema.Schema{
+		Type:         schema.TypeString,
+		ValidateFunc: validation.StringInSlice([]string{"DEV", "PROD"}, false),
+	},
+	Set:         schema.HashString,
+	Description: "Project environments of the repository, DEV and/or PROD. Only sent along with a project_key",
 }
 
-func mkRepoCreate(unpack UnpackFunc, read ReadFunc) func(d *schema.ResourceData, m interface{}) error {
+// repoProject is the part of a repository payload that places it in a project
+type repoProject struct {
+	ProjectKey   string   `json:"projectKey"`
+	Environments []string `json:"environments"`
+}
+
+// skipDefaultProjectKeySchema is shared by everything with a project_key the provider's can default
+var skipDefaultProjectKeySchema = &schema.Schema{
+	Type:        schema.TypeBool,
+	Optional:    true,
+	Description: "Don't put the resource in the provider's project_key when it's created without its own",
+}
 
-	return func(d *schema.ResourceData, m interface{}) error {
+// defaultProjectKey plans the provider's project_key for a resource created without its own, so the project shows in
+// the plan. Resources that already exist keep the project_key in state, setting the provider's later doesn't move them.
+// A plan can't tell an unset project_key from one only known after apply, so the latter needs skip_default_project_key.
+// A project_key on an artifactory without projects fails the plan
+func defaultProjectKey(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+	meta, ok := m.(*ProviderMetadata)
+	if !ok {
+		return nil
+	}
+	if _, ok := d.GetOk("project_key"); !ok && meta.ProjectKey != "" && d.Id() == "" && !d.Get("skip_default_project_key").(bool) {
+		if err := d.SetNew("project_key", meta.ProjectKey); err != nil {
+			return err
+		}
+	}
+	if d.Get("project_key").(string) == "" {
+		return nil
+	}
+	return meta.requireVersion("project_key", projectsMinVersion)
+}
+
+// projectFor is the project_key and project_environments of the resource. Environments without a project mean nothing
+// to artifactory, so they are dropped
+func projectFor(d *schema.ResourceData) repoProject {
+	project := repoProject{ProjectKey: d.Get("project_key").(string)}
+	if project.ProjectKey == "" {
+		return project
+	}
+	if v, ok := d.GetOk("project_environments"); ok {
+		project.Environments = castToStringArr(v.(*schema.Set).List())
+	}
+	return project
+}
+
+// withProject adds projectKey and environments to a repository payload. The payload structs come from several places,
+// some of them jfrog-client-go, so rather than giving each of them the fields they go in on the way out
+func withProject(repo interface{}, project repoProject) (interface{}, error) {
+	if project.ProjectKey == "" {
+		return repo, nil
+	}
+	raw, err := json.Marshal(repo)
+	if err != nil {
+		return nil, err
+	}
+	payload := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, err
+	}
+	payload["projectKey"] = project.ProjectKey
+	if len(project.Environments) > 0 {
+		payload["environments"] = project.Environments
+	}
+	return payload, nil
+}
+
+func mkRepoCreate(unpack UnpackFunc, read schema.ReadContextFunc) schema.CreateContextFunc {
+
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 		repo, key, err := unpack(d)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
+		}
+		if repo, err = withProject(repo, projectFor(d)); err != nil {
+			return diag.FromErr(err)
 		}
 		// repo must be a pointer
-		_, err = m.(*resty.Client).R().AddRetryCondition(retryOnMergeError).SetBody(repo).Put(repositoriesEndpoint + key)
+		unlock, err := lockDescriptor(ctx, m)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		_, err = m.(*ProviderMetadata).Client.R().SetContext(ctx).AddRetryCondition(retryOnMergeError).SetBody(repo).Put(repositoriesEndpoint + key)
+		unlock()
 
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		d.SetId(key)
-		return read(d, m)
+		return read(ctx, d, m)
 	}
 }
 
-func mkRepoRead(pack PackFunc, construct Constructor) func(d *schema.ResourceData, m interface{}) error {
-	return func(d *schema.ResourceData, m interface{}) error {
+func mkRepoRead(pack PackFunc, construct Constructor) schema.ReadContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 		repo := construct()
 		// repo must be a pointer
-		resp, err := m.(*resty.Client).R().SetResult(repo).Get(repositoriesEndpoint + d.Id())
+		resp, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).SetResult(repo).Get(repositoriesEndpoint + d.Id())
 
 		if err != nil {
-			if resp != nil && (resp.StatusCode() == http.StatusNotFound) {
+			if IsNotFound(err) {
 				d.SetId("")
 				return nil
 			}
-			return err
+			return diag.FromErr(err)
+		}
+		if err := pack(repo, d); err != nil {
+			return diag.FromErr(err)
 		}
-		return pack(repo, d)
+		// like the payloads, none of the structs know about the project. Reading it back also catches a repository
+		// moved to another project out of band
+		project := repoProject{}
+		if err := json.Unmarshal(resp.Body(), &project); err != nil {
+			return diag.FromErr(err)
+		}
+		setValue := mkLens(d)
+		setValue("project_key", project.ProjectKey)
+		errors := setValue("project_environments", castToInterfaceArr(project.Environments))
+		if errors != nil && len(errors) > 0 {
+			return diag.Errorf("failed saving state for repository %q", errors)
+		}
+		return nil
 	}
 }
 
-func mkRepoUpdate(unpack UnpackFunc, read ReadFunc) func(d *schema.ResourceData, m interface{}) error {
-	return func(d *schema.ResourceData, m interface{}) error {
+func mkRepoUpdate(unpack UnpackFunc, read schema.ReadContextFunc) schema.UpdateContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 		repo, key, err := unpack(d)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
+		}
+		if repo, err = withProject(repo, projectFor(d)); err != nil {
+			return diag.FromErr(err)
 		}
 		// repo must be a pointer
-		_, err = m.(*resty.Client).R().AddRetryCondition(retryOnMergeError).SetBody(repo).Post(repositoriesEndpoint + d.Id())
+		unlock, err := lockDescriptor(ctx, m)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
+		}
+		_, err = m.(*ProviderMetadata).Client.R().SetContext(ctx).AddRetryCondition(retryOnMergeError).SetBody(repo).Post(repositoriesEndpoint + d.Id())
+		unlock()
+		if err != nil {
+			return diag.FromErr(err)
 		}
 
 		d.SetId(key)
-		return read(d, m)
+		return read(ctx, d, m)
 	}
 }
 
-func deleteRepo(d *schema.ResourceData, m interface{}) error {
-	resp, err := m.(*resty.Client).R().Delete(repositoriesEndpoint + d.Id())
+func deleteRepo(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	unlock, err := lockDescriptor(ctx, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
+	_, err = m.(*ProviderMetadata).Client.R().SetContext(ctx).Delete(repositoriesEndpoint + d.Id())
 
-	if err != nil && (resp != nil && resp.StatusCode() == http.StatusNotFound) {
+	if IsNotFound(err) {
 		d.SetId("")
 		return nil
 	}
-	return err
+	return diag.FromErr(err)
 }
 
 var neverRetry = func(response *resty.Response, err error) bool {
 	return false
 }
 
-var retry400 = func(response *resty.Response, err error) bool {
-	return response.StatusCode() == 400
-}
-
 func checkRepo(id string, request *resty.Request) (*resty.Response, error) {
 	// artifactory returns 400 instead of 404. but regardless, it's an error
 	return request.Head(repositoriesEndpoint + id)
 }
 
-func repoExists(d *schema.ResourceData, m interface{}) (bool, error) {
-	_, err := checkRepo(d.Id(), m.(*resty.Client).R().AddRetryCondition(retry400))
-	return err == nil, err
-
-}
-
 var repoTypeValidator = validation.StringInSlice(repoTypesSupported, false)
 
 var repoKeyValidator = validation.All(
@@ -262,6 +379,9 @@ var repoTypesSupported = []string{
 	"vcs",
 }
 var baseLocalRepoSchema = map[string]*schema.Schema{
+	"project_key":              repoProjectKeySchema,
+	"skip_default_project_key": skipDefaultProjectKeySchema,
+	"project_environments":     repoProjectEnvironmentsSchema,
 	"key": {
 		Type:         schema.TypeString,
 		Required:     true,
@@ -331,6 +451,9 @@ var baseLocalRepoSchema = map[string]*schema.Schema{
 	},
 }
 var baseRemoteSchema = map[string]*schema.Schema{
+	"project_key":              repoProjectKeySchema,
+	"skip_default_project_key": skipDefaultProjectKeySchema,
+	"project_environments":     repoProjectEnvironmentsSchema,
 	"key": {
 		Type:         schema.TypeString,
 		Required:     true,
@@ -538,6 +661,9 @@ var baseRemoteSchema = map[string]*schema.Schema{
 	},
 }
 var baseVirtualRepoSchema = map[string]*schema.Schema{
+	"project_key":              repoProjectKeySchema,
+	"skip_default_project_key": skipDefaultProjectKeySchema,
+	"project_environments":     repoProjectEnvironmentsSchema,
 	"key": {
 		Type:     schema.TypeString,
 		Required: true,
@@ -793,16 +919,24 @@ func universalPack(payload interface{}, d *schema.ResourceData) error {
 	return nil
 }
 
+// upgradeRemoteRepoState hashes the password of remote repositories written to state before it was hashed,
+// which otherwise shows up as a password change on every plan
+func upgradeRemoteRepoState(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	hashPasswordInState(rawState)
+	return rawState, nil
+}
+
 func mkResourceSchema(skeema map[string]*schema.Schema, packer PackFunc, unpack UnpackFunc, constructor Constructor) *schema.Resource {
 	var reader = mkRepoRead(packer, constructor)
 	return &schema.Resource{
-		Create: mkRepoCreate(unpack, reader),
-		Read:   reader,
-		Update: mkRepoUpdate(unpack, reader),
-		Delete: deleteRepo,
-		Exists: repoExists,
+		CreateContext: mkRepoCreate(unpack, reader),
+		ReadContext:   reader,
+		UpdateContext: mkRepoUpdate(unpack, reader),
+		DeleteContext: deleteRepo,
+		Timeouts:      defaultResourceTimeouts(),
+		CustomizeDiff: defaultProjectKey,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: skeema,
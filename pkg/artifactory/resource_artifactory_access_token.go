@@ -1,18 +1,20 @@
 package artifactory
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
-	"net/http"
 	"net/url"
-	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-resty/resty/v2"
 	"github.com/google/go-querystring/query"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -55,12 +57,16 @@ type AccessToken struct {
 
 func resourceArtifactoryAccessToken() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceAccessTokenCreate,
-		Read:   resourceAccessTokenRead,
-		Delete: resourceAccessTokenDelete,
+		CreateContext: resourceAccessTokenCreate,
+		ReadContext:   resourceAccessTokenRead,
+		UpdateContext: resourceAccessTokenUpdate,
+		DeleteContext: resourceAccessTokenDelete,
+		Timeouts:      defaultResourceTimeouts(),
+		// the legacy api can't refresh a token without changing its end_date, so a due token is always replaced
+		CustomizeDiff: planTokenRotation(accessTokenExpiry, nil, "access_token", "refresh_token"),
 
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -145,6 +151,7 @@ func resourceArtifactoryAccessToken() *schema.Resource {
 					},
 				},
 			},
+			"rotate_before": rotateBeforeSchema,
 			"access_token": {
 				Type:      schema.TypeString,
 				Computed:  true,
@@ -159,8 +166,8 @@ func resourceArtifactoryAccessToken() *schema.Resource {
 	}
 }
 
-func resourceAccessTokenCreate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*resty.Client)
+func resourceAccessTokenCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ProviderMetadata).Client
 	grantType := "client_credentials" // client_credentials is the only supported type
 
 	tokenOptions := AccessTokenOptions{}
@@ -168,13 +175,13 @@ func resourceAccessTokenCreate(d *schema.ResourceData, m interface{}) error {
 
 	date, expiresIn, err := getDate(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	tokenOptions.ExpiresIn = expiresIn
 	err = d.Set("end_date", date.Format(time.RFC3339))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	refreshable := resourceData.Get("refreshable").(bool)
@@ -186,41 +193,41 @@ func resourceAccessTokenCreate(d *schema.ResourceData, m interface{}) error {
 	tokenOptions.Username = resourceData.getString("username", false)
 
 	username := resourceData.Get("username").(string)
-	userExists, _ := checkUserExists(client, username)
+	userExists, _ := checkUserExists(ctx, client, username)
 
 	if !userExists && len(resourceData.Get("groups").([]interface{})) == 0 {
-		return fmt.Errorf("you must specify at least 1 group when creating a token for a non-existant user - %s, or correct the username", username)
+		return diag.Errorf("you must specify at least 1 group when creating a token for a non-existant user - %s, or correct the username", username)
 	}
 
-	err = unpackGroups(d, client, &tokenOptions)
+	err = unpackGroups(ctx, d, client, &tokenOptions)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	err = unpackAdminToken(d, &tokenOptions)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	accessToken := AccessToken{}
 	values, err := tokenOptsToValues(tokenOptions)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	_, err = m.(*resty.Client).R().
+	_, err = m.(*ProviderMetadata).Client.R().SetContext(ctx).
 		SetHeader("Content-Type", "application/x-www-form-urlencoded").
 		SetResult(&accessToken).
 		SetFormDataFromValues(values).Post("artifactory/api/security/token")
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(strconv.Itoa(schema.HashString(accessToken.AccessToken)))
 
 	err = d.Set("access_token", accessToken.AccessToken)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	refreshToken := ""
@@ -230,18 +237,104 @@ func resourceAccessTokenCreate(d *schema.ResourceData, m interface{}) error {
 
 	err = d.Set("refresh_token", refreshToken)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	return nil
 }
 
-func resourceAccessTokenRead(_ *schema.ResourceData, _ interface{}) error {
-	// Terraform requires that the read function is always implemented.
-	// However, Artifactory does not have an API to read a token.
+// AccessTokenInfo is an entry of the token listing, which has the revocable tokens: the ones that don't expire
+type AccessTokenInfo struct {
+	TokenId     string `json:"token_id"`
+	Issuer      string `json:"issuer"`
+	Subject     string `json:"subject"`
+	Expiry      int64  `json:"expiry"`
+	Refreshable bool   `json:"refreshable"`
+	IssuedAt    int64  `json:"issued_at"`
+}
+
+type AccessTokenList struct {
+	Tokens []AccessTokenInfo `json:"tokens"`
+}
+
+// resourceAccessTokenRead drops a token that has expired, or that doesn't expire and isn't listed anymore, which is
+// what a revoked one looks like. Artifactory doesn't list expiring tokens, nor revoke them, so there's nothing more
+// to check about those
+func resourceAccessTokenRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	expiry := tokenExpiry(d.Get("end_date").(string), d.Get("end_date_relative").(string))
+	if expiry != "" {
+		if end, err := time.Parse(time.RFC3339, expiry); err == nil && end.Before(time.Now()) {
+			log.Printf("[DEBUG] Token %s expired at %s", d.Id(), expiry)
+			d.SetId("")
+		}
+		return nil
+	}
+
+	tokenId, err := jwtTokenId(d.Get("access_token").(string))
+	if err != nil {
+		// imported, or from an artifactory that doesn't issue JWTs. Either way there's no telling it apart
+		log.Printf("[DEBUG] Token %s can't be looked up: %s", d.Id(), err)
+		return nil
+	}
+	tokens := AccessTokenList{}
+	_, err = m.(*ProviderMetadata).Client.R().SetContext(ctx).SetResult(&tokens).Get("artifactory/api/security/token")
+	if err != nil {
+		// only admins can list tokens. A user that created their own can't look it up either
+		if IsForbidden(err) {
+			log.Printf("[DEBUG] Token %s can't be looked up: %s", d.Id(), err)
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	for _, token := range tokens.Tokens {
+		if token.TokenId == tokenId {
+			return nil
+		}
+	}
+	log.Printf("[DEBUG] Token %s is no longer listed, it was revoked", d.Id())
+	d.SetId("")
 	return nil
 }
 
-func resourceAccessTokenDelete(d *schema.ResourceData, m interface{}) error {
+// jwtTokenId is the jti claim of a token, the token_id it's listed under. The token itself isn't part of any error
+func jwtTokenId(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", errors.New("not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return "", errors.New("the JWT payload isn't base64url")
+	}
+	claims := struct {
+		Jti string `json:"jti"`
+	}{}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Jti == "" {
+		return "", errors.New("the JWT has no jti")
+	}
+	return claims.Jti, nil
+}
+
+// resourceAccessTokenUpdate only ever sees rotate_before change, everything else replaces the token
+func resourceAccessTokenUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return resourceAccessTokenRead(ctx, d, m)
+}
+
+func accessTokenExpiry(d *schema.ResourceDiff) string {
+	return tokenExpiry(d.Get("end_date").(string), d.Get("end_date_relative").(string))
+}
+
+// tokenExpiry is end_date, except for a token created with a 0 end_date_relative: it never expires, and its
+// end_date is just when it was created
+func tokenExpiry(endDate, endDateRelative string) string {
+	if endDateRelative != "" {
+		if duration, err := time.ParseDuration(endDateRelative); err == nil && duration == 0 {
+			return ""
+		}
+	}
+	return endDate
+}
+
+func resourceAccessTokenDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	// Artifactory only allows you to revoke a token if the there is no expiry.
 	// Otherwise, Artifactory will ensure the token is revoked at the expiry time.
 	// https://www.jfrog.com/confluence/display/JFROG/Access+Tokens#AccessTokens-ViewingandRevokingTokens
@@ -258,7 +351,7 @@ func resourceAccessTokenDelete(d *schema.ResourceData, m interface{}) error {
 	// Convert end date relative to duration in seconds
 	duration, err := time.ParseDuration(endDateRelative)
 	if err != nil {
-		return fmt.Errorf("unable to parse `end_date_relative` (%s) as a duration", endDateRelative)
+		return diag.Errorf("unable to parse `end_date_relative` (%s) as a duration", endDateRelative)
 	}
 
 	// If the token has no duration, it does not expire.
@@ -268,22 +361,19 @@ func resourceAccessTokenDelete(d *schema.ResourceData, m interface{}) error {
 		revokeOptions := AccessTokenRevokeOptions{}
 		revokeOptions.Token = d.Get("access_token").(string)
 		values, err := query.Values(revokeOptions)
-		resp, err := m.(*resty.Client).R().
+		_, err = m.(*ProviderMetadata).Client.R().SetContext(ctx).
 			SetHeader("Content-Type", "application/x-www-form-urlencoded").
 			SetFormDataFromValues(values).Post("artifactory/api/security/token/revoke")
 		if err != nil {
-			if resp != nil {
-				if resp.StatusCode() == http.StatusNotFound {
-					log.Printf("[DEBUG] Token Revoked")
-					return nil
-				}
-				// the original atlassian code considered any error code fine. However, expiring tokens can't be revoked
-				regex := regexp.MustCompile(`.*Token not revocable.*`)
-				if regex.MatchString(string(resp.Body()[:])) {
-					return nil
-				}
+			if IsNotFound(err) {
+				log.Printf("[DEBUG] Token Revoked")
+				return nil
+			}
+			// the original atlassian code considered any error code fine. However, expiring tokens can't be revoked
+			if errorContains(err, "Token not revocable") {
+				return nil
 			}
-			return err
+			return diag.FromErr(err)
 		}
 		return nil
 	}
@@ -294,13 +384,13 @@ func resourceAccessTokenDelete(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
-func unpackGroups(d *schema.ResourceData, client *resty.Client, tokenOptions *AccessTokenOptions) error {
+func unpackGroups(ctx context.Context, d *schema.ResourceData, client *resty.Client, tokenOptions *AccessTokenOptions) error {
 	if srcGroups, ok := d.GetOk("groups"); ok {
 		groups := make([]string, len(srcGroups.([]interface{})))
 		for i, group := range srcGroups.([]interface{}) {
 			groups[i] = group.(string)
 
-			if exist, err := checkGroupExists(client, groups[i]); !exist {
+			if exist, err := checkGroupExists(ctx, client, groups[i]); !exist {
 				return err
 			}
 		}
@@ -327,21 +417,19 @@ func unpackAdminToken(d *schema.ResourceData, tokenOptions *AccessTokenOptions)
 	return nil
 }
 
-func checkUserExists(client *resty.Client, name string) (bool, error) {
-	resp, err := client.R().Head("artifactory/api/security/users/" + name)
+func checkUserExists(ctx context.Context, client *resty.Client, name string) (bool, error) {
+	_, err := client.R().SetContext(ctx).Head("artifactory/api/security/users/" + name)
 	if err != nil {
 		// If there is an error, it possible the user does not exist.
-		if resp != nil {
-			// Check if the user does not exist in artifactory
-			if resp.StatusCode() == http.StatusNotFound {
-				return false, errors.New("user must exist in artifactory")
-			}
+		// Check if the user does not exist in artifactory
+		if IsNotFound(err) {
+			return false, errors.New("user must exist in artifactory")
+		}
 
-			// If we cannot search for Users, the current user is not an admin
-			// So, we'll let this through and let the CreateToken function error if there is a misconfiguration.
-			if resp.StatusCode() == http.StatusForbidden {
-				return true, nil
-			}
+		// If we cannot search for Users, the current user is not an admin
+		// So, we'll let this through and let the CreateToken function error if there is a misconfiguration.
+		if IsForbidden(err) {
+			return true, nil
 		}
 		return false, err
 	}
@@ -349,21 +437,19 @@ func checkUserExists(client *resty.Client, name string) (bool, error) {
 	return true, nil
 }
 
-func checkGroupExists(client *resty.Client, name string) (bool, error) {
-	resp, err := client.R().Head(groupsEndpoint + name)
+func checkGroupExists(ctx context.Context, client *resty.Client, name string) (bool, error) {
+	_, err := client.R().SetContext(ctx).Head(groupsEndpoint + name)
 	// If there is an error, it possible the group does not exist.
 	if err != nil {
-		if resp != nil {
-			// Check if the group does not exist in artifactory
-			if resp.StatusCode() == http.StatusNotFound {
-				return false, errors.New("group must exist in artifactory")
-			}
+		// Check if the group does not exist in artifactory
+		if IsNotFound(err) {
+			return false, errors.New("group must exist in artifactory")
+		}
 
-			// If we cannot search for groups, the current user is not an admin and they can only specify groups they belong to.
-			// Therefore, we return true and rely on Artifactory to error if the user has specified a wrong group.
-			if resp.StatusCode() == http.StatusForbidden {
-				return true, nil
-			}
+		// If we cannot search for groups, the current user is not an admin and they can only specify groups they belong to.
+		// Therefore, we return true and rely on Artifactory to error if the user has specified a wrong group.
+		if IsForbidden(err) {
+			return true, nil
 		}
 
 		return false, err
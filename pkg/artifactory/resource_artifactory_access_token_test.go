@@ -1,15 +1,19 @@
 package artifactory
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"regexp"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestAccAccessTokenAudienceBad(t *testing.T) {
@@ -445,3 +449,74 @@ func TestTokenOptsToValuesNonExpiring(t *testing.T) {
 		t.Error("`expires_in` not correctly set when creating non-expiring tokens")
 	}
 }
+
+func TestAccessTokenRevocation(t *testing.T) {
+	meta, client := newFakeMeta(t)
+	ctx := context.Background()
+	res := resourceArtifactoryAccessToken()
+	if _, err := client.R().SetBody(Group{Name: "readers"}).Put(groupsEndpoint + "readers"); err != nil {
+		t.Fatal(err)
+	}
+
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{
+		"username":          "ci",
+		"end_date_relative": "0s",
+		"groups":            []interface{}{"readers"},
+	})
+	if diags := res.CreateContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("create failed: %v", diags)
+	}
+	if diags := res.ReadContext(ctx, d, meta); diags.HasError() || d.Id() == "" {
+		t.Fatalf("expected a live token to stay, got %q %v", d.Id(), diags)
+	}
+
+	// revoked in the UI
+	if _, err := client.R().SetFormData(map[string]string{"token": d.Get("access_token").(string)}).
+		Post("artifactory/api/security/token/revoke"); err != nil {
+		t.Fatal(err)
+	}
+	if diags := res.ReadContext(ctx, d, meta); diags.HasError() || d.Id() != "" {
+		t.Errorf("expected a revoked token to leave the state, got %q %v", d.Id(), diags)
+	}
+
+	expired := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{
+		"username": "ci",
+		"end_date": time.Now().Add(-time.Hour).UTC().Format(time.RFC3339),
+	})
+	expired.SetId("1234")
+	if diags := res.ReadContext(ctx, expired, meta); diags.HasError() || expired.Id() != "" {
+		t.Errorf("expected an expired token to leave the state, got %q %v", expired.Id(), diags)
+	}
+}
+
+func TestAccessTokenReadByNonAdmin(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	client, err := buildResty(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := resourceArtifactoryAccessToken()
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{"username": "ci"})
+	d.SetId("1234")
+	// {"jti":"abc","sub":"jfrt@fake/users/ci"}
+	if err := d.Set("access_token", "eyJ0eXAiOiJKV1QifQ.eyJqdGkiOiJhYmMiLCJzdWIiOiJqZnJ0QGZha2UvdXNlcnMvY2kifQ.c2ln"); err != nil {
+		t.Fatal(err)
+	}
+	if diags := res.ReadContext(context.Background(), d, &ProviderMetadata{Client: client}); diags.HasError() || d.Id() == "" {
+		t.Errorf("expected a token that can't be listed to stay, got %q %v", d.Id(), diags)
+	}
+}
+
+func TestJwtTokenId(t *testing.T) {
+	// {"jti":"abc","sub":"jfrt@fake/users/ci"}
+	id, err := jwtTokenId("eyJ0eXAiOiJKV1QifQ.eyJqdGkiOiJhYmMiLCJzdWIiOiJqZnJ0QGZha2UvdXNlcnMvY2kifQ.c2ln")
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", id)
+
+	_, err = jwtTokenId("secretsecretsecret")
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret", "the token must never end up in an error")
+}
@@ -1,11 +1,11 @@
 package artifactory
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 
-	"github.com/go-resty/resty/v2"
-
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -17,12 +17,13 @@ const apiKeyEndpoint = "artifactory/api/security/apiKey"
 
 func resourceArtifactoryApiKey() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceApiKeyCreate,
-		Read:   resourceApiKeyRead,
-		Delete: apiKeyRevoke,
+		CreateContext: resourceApiKeyCreate,
+		ReadContext:   resourceApiKeyRead,
+		DeleteContext: apiKeyRevoke,
+		Timeouts:      defaultResourceTimeouts(),
 
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -48,36 +49,36 @@ func packApiKey(apiKey string, d *schema.ResourceData) error {
 	return nil
 }
 
-func resourceApiKeyCreate(d *schema.ResourceData, m interface{}) error {
+func resourceApiKeyCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	data := make(map[string]string)
 
-	_, err := m.(*resty.Client).R().SetResult(&data).Post(apiKeyEndpoint)
+	_, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).SetResult(&data).Post(apiKeyEndpoint)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if apiKey, ok := data["apiKey"]; ok {
 		d.SetId(strconv.Itoa(schema.HashString(apiKey)))
-		return resourceApiKeyRead(d, m)
+		return resourceApiKeyRead(ctx, d, m)
 	}
-	return fmt.Errorf("received no error when creating apikey, but also got no apikey")
+	return diag.Errorf("received no error when creating apikey, but also got no apikey")
 }
 
-func resourceApiKeyRead(d *schema.ResourceData, m interface{}) error {
+func resourceApiKeyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	data := make(map[string]string)
-	_, err := m.(*resty.Client).R().SetResult(&data).Get(apiKeyEndpoint)
+	_, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).SetResult(&data).Get(apiKeyEndpoint)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	key := data["apiKey"]
 	if key == "" {
 		d.SetId("")
 		return nil
 	}
-	return packApiKey(key, d)
+	return diag.FromErr(packApiKey(key, d))
 }
 
-func apiKeyRevoke(_ *schema.ResourceData, m interface{}) error {
-	_, err := m.(*resty.Client).R().Delete(apiKeyEndpoint)
-	return err
+func apiKeyRevoke(ctx context.Context, _ *schema.ResourceData, m interface{}) diag.Diagnostics {
+	_, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).Delete(apiKeyEndpoint)
+	return diag.FromErr(err)
 }
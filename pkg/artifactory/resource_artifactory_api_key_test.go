@@ -4,8 +4,6 @@ import (
 	"fmt"
 	"testing"
 
-	"github.com/go-resty/resty/v2"
-
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
@@ -33,7 +31,7 @@ func TestAccApiKey(t *testing.T) {
 func testAccCheckApiKeyDestroy(id string) func(*terraform.State) error {
 	return func(s *terraform.State) error {
 		provider, _ := testAccProviders["artifactory"]()
-		client := provider.Meta().(*resty.Client)
+		client := provider.Meta().(*ProviderMetadata).Client
 		rs, ok := s.RootModule().Resources[id]
 
 		if !ok {
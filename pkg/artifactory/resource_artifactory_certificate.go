@@ -11,7 +11,7 @@ import (
 	"os"
 	"strings"
 
-	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -31,14 +31,14 @@ type CertificateDetails struct {
 
 func resourceArtifactoryCertificate() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceCertificateCreate,
-		Read:   resourceCertificateRead,
-		Update: resourceCertificateUpdate,
-		Delete: resourceCertificateDelete,
-		Exists: resourceCertificateExists,
+		CreateContext: resourceCertificateCreate,
+		ReadContext:   resourceCertificateRead,
+		UpdateContext: resourceCertificateUpdate,
+		DeleteContext: resourceCertificateDelete,
+		Timeouts:      defaultResourceTimeouts(),
 
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -164,10 +164,10 @@ func calculateFingerPrint(pemData string) (string, error) {
 	return formatFingerPrint(fingerprint[:]), nil
 }
 
-func findCertificate(alias string, m interface{}) (*CertificateDetails, error) {
-	c := m.(*resty.Client)
+func findCertificate(ctx context.Context, alias string, m interface{}) (*CertificateDetails, error) {
+	c := m.(*ProviderMetadata).Client
 	certificates := new([]CertificateDetails)
-	_, err := c.R().SetResult(certificates).Get(endpoint)
+	_, err := c.R().SetContext(ctx).SetResult(certificates).Get(endpoint)
 
 	if err != nil {
 		return nil, err
@@ -183,15 +183,15 @@ func findCertificate(alias string, m interface{}) (*CertificateDetails, error) {
 	return nil, nil
 }
 
-func resourceCertificateCreate(d *schema.ResourceData, m interface{}) error {
+func resourceCertificateCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	d.SetId(d.Get("alias").(string))
-	return resourceCertificateUpdate(d, m)
+	return resourceCertificateUpdate(ctx, d, m)
 }
 
-func resourceCertificateRead(d *schema.ResourceData, m interface{}) error {
-	cert, err := findCertificate(d.Id(), m)
+func resourceCertificateRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	cert, err := findCertificate(ctx, d.Id(), m)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if cert != nil {
@@ -205,7 +205,7 @@ func resourceCertificateRead(d *schema.ResourceData, m interface{}) error {
 		errors := setValue("valid_until", (*cert).ValidUntil)
 
 		if errors != nil && len(errors) > 0 {
-			return fmt.Errorf("failed to pack certificate %q", errors)
+			return diag.Errorf("failed to pack certificate %q", errors)
 		}
 
 		return nil
@@ -252,33 +252,28 @@ func getContentFromData(d *schema.ResourceData) (string, error) {
 	return "", fmt.Errorf("mmm, couldn't get content or file. You need either a content or a file")
 }
 
-func resourceCertificateUpdate(d *schema.ResourceData, m interface{}) error {
+func resourceCertificateUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	content, err := getContentFromData(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	_, err = m.(*resty.Client).R().SetBody(content).SetHeader("content-type", "text/plain").Post(endpoint + d.Id())
+	_, err = m.(*ProviderMetadata).Client.R().SetContext(ctx).SetBody(content).SetHeader("content-type", "text/plain").Post(endpoint + d.Id())
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	return resourceCertificateRead(d, m)
+	return resourceCertificateRead(ctx, d, m)
 }
 
-func resourceCertificateDelete(d *schema.ResourceData, m interface{}) error {
-	_, err := m.(*resty.Client).R().Delete(endpoint + d.Id())
-	if err != nil {
-		return err
+func resourceCertificateDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	_, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).Delete(endpoint + d.Id())
+	if err != nil && !IsNotFound(err) {
+		return diag.FromErr(err)
 	}
 
 	d.SetId("")
 
 	return nil
 }
-
-func resourceCertificateExists(d *schema.ResourceData, m interface{}) (bool, error) {
-	cert, err := findCertificate(d.Id(), m)
-	return err == nil && cert != nil, err
-}
@@ -1,6 +1,7 @@
 package artifactory
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strings"
@@ -151,7 +152,7 @@ func testAccCheckCertificateDestroy(id string) func(*terraform.State) error {
 			return fmt.Errorf("err: Resource id[%s] not found", id)
 		}
 		provider, _ := testAccProviders["artifactory"]()
-		cert, err := findCertificate(id, provider.Meta())
+		cert, err := findCertificate(context.Background(), id, provider.Meta())
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,194 @@
+package artifactory
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"gopkg.in/yaml.v2"
+)
+
+type CrowdSecurity struct {
+	Crowd CrowdSettingsWrapper `yaml:"security"`
+}
+
+type CrowdSettingsWrapper struct {
+	Settings CrowdSettings `yaml:"crowdSettings" xml:"crowdSettings"`
+}
+
+type CrowdSettings struct {
+	EnableIntegration          bool   `yaml:"enableIntegration" xml:"enableIntegration"`
+	ServerUrl                  string `yaml:"serverUrl" xml:"serverUrl"`
+	ApplicationName            string `yaml:"applicationName" xml:"applicationName"`
+	Password                   string `yaml:"password,omitempty" xml:"password"`
+	SessionValidationInterval  int    `yaml:"sessionValidationInterval" xml:"sessionValidationInterval"`
+	UseDefaultProxy            bool   `yaml:"useDefaultProxy" xml:"useDefaultProxy"`
+	NoAutoUserCreation         bool   `yaml:"noAutoUserCreation" xml:"noAutoUserCreation"`
+	AllowUserToAccessProfile   bool   `yaml:"allowUserToAccessProfile" xml:"allowUserToAccessProfile"`
+	DirectAuthentication       bool   `yaml:"directAuthentication" xml:"directAuthentication"`
+	OverrideAllGroupsUponLogin bool   `yaml:"overrideAllGroupsUponLogin" xml:"overrideAllGroupsUponLogin"`
+}
+
+// CrowdConfiguration is the part of artifactory.config.xml with the crowd settings
+type CrowdConfiguration struct {
+	Crowd CrowdSettingsWrapper `xml:"security"`
+}
+
+func resourceArtifactoryCrowdSettings() *schema.Resource {
+	return &schema.Resource{
+		UpdateContext: resourceCrowdSettingsUpdate,
+		CreateContext: resourceCrowdSettingsUpdate,
+		DeleteContext: resourceCrowdSettingsDelete,
+		ReadContext:   resourceCrowdSettingsRead,
+		Timeouts:      defaultResourceTimeouts(),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"enable": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"server_url": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+				Description:  "The url of Crowd or Jira, eg https://crowd.example.com/crowd",
+			},
+			"application_name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				StateFunc:   getMD5Hash,
+				Description: "The password of the application. Artifactory only keeps it encrypted, so only its hash is in state",
+			},
+			"session_validation_interval": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Minutes a session is trusted before asking Crowd again. 0 asks on every request",
+			},
+			"use_default_proxy": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"no_auto_user_creation": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"allow_user_to_access_profile": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"direct_authentication": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Log users in with their Crowd password, instead of through the Crowd SSO cookie",
+			},
+			"override_all_groups_upon_login": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Replace the groups of a user with their Crowd groups on every login",
+			},
+		},
+	}
+}
+
+func resourceCrowdSettingsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	config := CrowdConfiguration{}
+	if err := getConfiguration(ctx, m, &config); err != nil {
+		return diag.Errorf("failed to retrieve the crowd settings during Read: %s", err)
+	}
+	if config.Crowd.Settings.ServerUrl == "" {
+		d.SetId("")
+		return nil
+	}
+	return packCrowdSecurity(&CrowdSecurity{config.Crowd}, d)
+}
+
+func resourceCrowdSettingsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	unpacked := unpackCrowdSecurity(d)
+	content, err := yaml.Marshal(&unpacked)
+
+	if err != nil {
+		return diag.Errorf("failed to marshal crowd settings during Update")
+	}
+
+	err = sendConfigurationPatch(ctx, content, m)
+	if err != nil {
+		return diag.Errorf("failed to send PATCH request to Artifactory during Update: %s", err)
+	}
+
+	// we should only have one crowd settings resource, using same id
+	d.SetId("crowd_settings")
+	return resourceCrowdSettingsRead(ctx, d, m)
+}
+
+func resourceCrowdSettingsDelete(ctx context.Context, _ *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var content = `
+security:
+  crowdSettings: ~
+`
+
+	err := sendConfigurationPatch(ctx, []byte(content), m)
+	if err != nil {
+		return diag.Errorf("failed to send PATCH request to Artifactory during Delete: %s", err)
+	}
+	return nil
+}
+
+// unpackCrowdSecurity only sends the password when it changed. What's in state is its hash, and the patch leaves out
+// what it doesn't name
+func unpackCrowdSecurity(s *schema.ResourceData) *CrowdSecurity {
+	d := &ResourceData{s}
+	security := new(CrowdSecurity)
+
+	security.Crowd.Settings = CrowdSettings{
+		EnableIntegration:          d.getBool("enable", false),
+		ServerUrl:                  d.getString("server_url", false),
+		ApplicationName:            d.getString("application_name", false),
+		Password:                   d.getString("password", true),
+		SessionValidationInterval:  d.getInt("session_validation_interval", false),
+		UseDefaultProxy:            d.getBool("use_default_proxy", false),
+		NoAutoUserCreation:         d.getBool("no_auto_user_creation", false),
+		AllowUserToAccessProfile:   d.getBool("allow_user_to_access_profile", false),
+		DirectAuthentication:       d.getBool("direct_authentication", false),
+		OverrideAllGroupsUponLogin: d.getBool("override_all_groups_upon_login", false),
+	}
+	return security
+}
+
+// packCrowdSecurity leaves the password alone, the configuration only has it encrypted
+func packCrowdSecurity(s *CrowdSecurity, d *schema.ResourceData) diag.Diagnostics {
+	setValue := mkLens(d)
+	settings := s.Crowd.Settings
+
+	setValue("enable", settings.EnableIntegration)
+	setValue("server_url", settings.ServerUrl)
+	setValue("application_name", settings.ApplicationName)
+	setValue("session_validation_interval", settings.SessionValidationInterval)
+	setValue("use_default_proxy", settings.UseDefaultProxy)
+	setValue("no_auto_user_creation", settings.NoAutoUserCreation)
+	setValue("allow_user_to_access_profile", settings.AllowUserToAccessProfile)
+	setValue("direct_authentication", settings.DirectAuthentication)
+	errors := setValue("override_all_groups_upon_login", settings.OverrideAllGroupsUponLogin)
+
+	if errors != nil && len(errors) > 0 {
+		return diag.Errorf("failed to pack crowd settings %q", errors)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package artifactory
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+const CrowdSettingsTemplateFull = `
+resource "artifactory_crowd_settings" "crowd" {
+	enable                      = true
+	server_url                  = "https://crowd.example.com/crowd"
+	application_name            = "artifactory"
+	password                    = "secret"
+	session_validation_interval = 5
+	direct_authentication       = true
+}`
+
+func TestAccCrowdSettings_full(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		CheckDestroy:      testAccCrowdSettingsDestroy("artifactory_crowd_settings.crowd"),
+		ProviderFactories: testAccProviders,
+
+		Steps: []resource.TestStep{
+			{
+				Config: CrowdSettingsTemplateFull,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("artifactory_crowd_settings.crowd", "server_url", "https://crowd.example.com/crowd"),
+					resource.TestCheckResourceAttr("artifactory_crowd_settings.crowd", "application_name", "artifactory"),
+					resource.TestCheckResourceAttr("artifactory_crowd_settings.crowd", "password", getMD5Hash("secret")),
+					resource.TestCheckResourceAttr("artifactory_crowd_settings.crowd", "session_validation_interval", "5"),
+					resource.TestCheckResourceAttr("artifactory_crowd_settings.crowd", "direct_authentication", "true"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCrowdSettingsDestroy(id string) func(*terraform.State) error {
+	return func(s *terraform.State) error {
+		provider, _ := testAccProviders["artifactory"]()
+
+		_, ok := s.RootModule().Resources[id]
+		if !ok {
+			return fmt.Errorf("error: resource id [%s] not found", id)
+		}
+		config := CrowdConfiguration{}
+		if err := getConfiguration(context.Background(), provider.Meta(), &config); err != nil {
+			return err
+		}
+		if config.Crowd.Settings.EnableIntegration {
+			return fmt.Errorf("error: crowd integration is still enabled")
+		}
+		return nil
+	}
+}
+
+func TestCrowdSettingsPassword(t *testing.T) {
+	meta, _ := newFakeMeta(t)
+	ctx := context.Background()
+	res := resourceArtifactoryCrowdSettings()
+	// the fake encrypts passwords as AM.<base64>
+	sentPassword := func() string {
+		config := CrowdConfiguration{}
+		if err := getConfiguration(ctx, meta, &config); err != nil {
+			t.Fatal(err)
+		}
+		plain, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(config.Crowd.Settings.Password, "AM."))
+		return string(plain)
+	}
+
+	raw := map[string]interface{}{
+		"server_url":       "https://crowd.example.com/crowd",
+		"application_name": "artifactory",
+		"password":         "secret",
+	}
+	d := schema.TestResourceDataRaw(t, res.Schema, raw)
+	if diags := res.CreateContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("create failed: %v", diags)
+	}
+	state := d.State()
+	assert.Equal(t, getMD5Hash("secret"), state.Attributes["password"], "only the hash of the password is kept")
+	assert.Equal(t, "secret", sentPassword())
+
+	// an update that leaves the password alone mustn't send the hash in its place
+	raw["session_validation_interval"] = 5
+	diff, err := res.Diff(ctx, state, terraform.NewResourceConfigRaw(raw), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.Attributes["password"] != nil {
+		t.Errorf("expected the same password to be no change, got %v", diff.Attributes["password"])
+	}
+	updated, diags := res.Apply(ctx, state, diff, meta)
+	if diags.HasError() {
+		t.Fatalf("update failed: %v", diags)
+	}
+	assert.Equal(t, "5", updated.Attributes["session_validation_interval"])
+	assert.Equal(t, "secret", sentPassword())
+}
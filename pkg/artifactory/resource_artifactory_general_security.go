@@ -3,11 +3,10 @@ package artifactory
 import (
 	"context"
 
-	"github.com/go-resty/resty/v2"
-
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"gopkg.in/yaml.v2"
 )
 
@@ -15,19 +14,94 @@ type GeneralSecurity struct {
 	GeneralSettings `yaml:"security" json:"security"`
 }
 
+// GeneralSecurityConfiguration is the part of artifactory.config.xml with the general security settings
+type GeneralSecurityConfiguration struct {
+	Settings GeneralSettings `xml:"security"`
+}
+
 type GeneralSettings struct {
-	AnonAccessEnabled bool `yaml:"anonAccessEnabled" json:"anonAccessEnabled"`
+	AnonAccessEnabled         bool             `yaml:"anonAccessEnabled" json:"anonAccessEnabled" xml:"anonAccessEnabled"`
+	HideUnauthorizedResources bool             `yaml:"hideUnauthorizedResources" json:"hideUnauthorizedResources" xml:"hideUnauthorizedResources"`
+	PasswordSettings          PasswordSettings `yaml:"passwordSettings" json:"passwordSettings" xml:"passwordSettings"`
+	UserLockPolicy            UserLockPolicy   `yaml:"userLockPolicy" json:"userLockPolicy" xml:"userLockPolicy"`
+	ApiKeySettings            ApiKeySettings   `yaml:"apiKeySettings" json:"apiKeySettings" xml:"apiKeySettings"`
+}
+
+type PasswordSettings struct {
+	EncryptionPolicy string                   `yaml:"encryptionPolicy" json:"encryptionPolicy" xml:"encryptionPolicy"`
+	ExpirationPolicy PasswordExpirationPolicy `yaml:"expirationPolicy" json:"expirationPolicy" xml:"expirationPolicy"`
+}
+
+type PasswordExpirationPolicy struct {
+	Enabled        bool `yaml:"enabled" json:"enabled" xml:"enabled"`
+	PasswordMaxAge int  `yaml:"passwordMaxAge" json:"passwordMaxAge" xml:"passwordMaxAge"`
+	NotifyByEmail  bool `yaml:"notifyByEmail" json:"notifyByEmail" xml:"notifyByEmail"`
+}
+
+type UserLockPolicy struct {
+	Enabled       bool `yaml:"enabled" json:"enabled" xml:"enabled"`
+	LoginAttempts int  `yaml:"loginAttempts" json:"loginAttempts" xml:"loginAttempts"`
 }
 
+type ApiKeySettings struct {
+	CreationEnabled bool `yaml:"creationEnabled" json:"creationEnabled" xml:"creationEnabled"`
+	UsageEnabled    bool `yaml:"usageEnabled" json:"usageEnabled" xml:"usageEnabled"`
+}
+
+// generalSecurityPatch only names the settings in the configuration, the rest stay as they were set in the UI
+type generalSecurityPatch struct {
+	Security generalSettingsPatch `yaml:"security"`
+}
+
+type generalSettingsPatch struct {
+	AnonAccessEnabled         bool                   `yaml:"anonAccessEnabled"`
+	HideUnauthorizedResources *bool                  `yaml:"hideUnauthorizedResources,omitempty"`
+	PasswordSettings          *passwordSettingsPatch `yaml:"passwordSettings,omitempty"`
+	UserLockPolicy            *userLockPolicyPatch   `yaml:"userLockPolicy,omitempty"`
+	ApiKeySettings            *apiKeySettingsPatch   `yaml:"apiKeySettings,omitempty"`
+}
+
+type passwordSettingsPatch struct {
+	EncryptionPolicy *string                        `yaml:"encryptionPolicy,omitempty"`
+	ExpirationPolicy *passwordExpirationPolicyPatch `yaml:"expirationPolicy,omitempty"`
+}
+
+type passwordExpirationPolicyPatch struct {
+	Enabled        *bool `yaml:"enabled,omitempty"`
+	PasswordMaxAge *int  `yaml:"passwordMaxAge,omitempty"`
+	NotifyByEmail  *bool `yaml:"notifyByEmail,omitempty"`
+}
+
+type userLockPolicyPatch struct {
+	Enabled       *bool `yaml:"enabled,omitempty"`
+	LoginAttempts *int  `yaml:"loginAttempts,omitempty"`
+}
+
+type apiKeySettingsPatch struct {
+	CreationEnabled *bool `yaml:"creationEnabled,omitempty"`
+	UsageEnabled    *bool `yaml:"usageEnabled,omitempty"`
+}
+
+// defaultGeneralSecurity is what a fresh artifactory has, and what deleting the resource goes back to
+var defaultGeneralSecurity = GeneralSecurity{GeneralSettings{
+	PasswordSettings: PasswordSettings{
+		EncryptionPolicy: "supported",
+		ExpirationPolicy: PasswordExpirationPolicy{PasswordMaxAge: 60, NotifyByEmail: true},
+	},
+	UserLockPolicy: UserLockPolicy{LoginAttempts: 5},
+	ApiKeySettings: ApiKeySettings{CreationEnabled: true, UsageEnabled: true},
+}}
+
 func resourceArtifactoryGeneralSecurity() *schema.Resource {
 	return &schema.Resource{
 		UpdateContext: resourceGeneralSecurityUpdate,
 		CreateContext: resourceGeneralSecurityUpdate,
 		DeleteContext: resourceGeneralSecurityDelete,
 		ReadContext:   resourceGeneralSecurityRead,
+		Timeouts:      defaultResourceTimeouts(),
 
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -36,22 +110,69 @@ func resourceArtifactoryGeneralSecurity() *schema.Resource {
 				Optional: true,
 				Default:  false,
 			},
+			"hide_unauthorized_resources": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Answer 404 instead of 401/403 for resources the user can't see",
+			},
+			"password_encryption_policy": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{"supported", "required", "unsupported"}, false),
+				Description:  "Whether clients must use encrypted passwords: supported, required or unsupported",
+			},
+			"password_expiration_enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Computed: true,
+			},
+			"password_max_age_days": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"password_expiration_notify_by_email": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Computed: true,
+			},
+			"user_lock_enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Computed: true,
+			},
+			"user_lock_login_attempts": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntBetween(1, 100),
+				Description:  "Failed logins before a user is locked out, with user_lock_enabled",
+			},
+			"api_key_creation_enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Computed: true,
+			},
+			"api_key_usage_enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Computed: true,
+			},
 		},
 	}
 }
 
+// resourceGeneralSecurityRead reads the settings out of artifactory.config.xml, securityconfig only has
+// anonAccessEnabled
 func resourceGeneralSecurityRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*resty.Client)
-
-	generalSettings := GeneralSettings{}
-
-	_, err := c.R().SetResult(&generalSettings).Get("artifactory/api/securityconfig")
-	if err != nil {
-		return diag.Errorf("failed to retrieve data from <base_url>/artifactory/api/securityconfig during Read")
+	config := GeneralSecurityConfiguration{}
+	if err := getConfiguration(ctx, m, &config); err != nil {
+		return diag.Errorf("failed to retrieve the general security settings during Read: %s", err)
 	}
-
-	s := GeneralSecurity{GeneralSettings: generalSettings}
-	packDiag := packGeneralSecurity(&s, d)
+	packDiag := packGeneralSecurity(&GeneralSecurity{GeneralSettings: config.Settings}, d)
 
 	if packDiag != nil {
 		return packDiag
@@ -66,15 +187,15 @@ func resourceGeneralSecurityRead(ctx context.Context, d *schema.ResourceData, m
 
 func resourceGeneralSecurityUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	unpacked := unpackGeneralSecurity(d)
-	content, err := yaml.Marshal(&unpacked)
+	content, err := yaml.Marshal(unpacked)
 
 	if err != nil {
 		return diag.Errorf("failed to marshal general security settings during Update")
 	}
 
-	err = sendConfigurationPatch(content, m)
+	err = sendConfigurationPatch(ctx, content, m)
 	if err != nil {
-		return diag.Errorf("failed to send PATCH request to Artifactory during Update")
+		return diag.Errorf("failed to send PATCH request to Artifactory during Update: %s", err)
 	}
 
 	// we should only have one general security settings resource, using same id
@@ -82,36 +203,97 @@ func resourceGeneralSecurityUpdate(ctx context.Context, d *schema.ResourceData,
 	return resourceGeneralSecurityRead(ctx, d, m)
 }
 
-func resourceGeneralSecurityDelete(_ context.Context, _ *schema.ResourceData, m interface{}) diag.Diagnostics {
-	var content = `
-security:
-  anonAccessEnabled: false
-`
+func resourceGeneralSecurityDelete(ctx context.Context, _ *schema.ResourceData, m interface{}) diag.Diagnostics {
+	content, err := yaml.Marshal(&defaultGeneralSecurity)
+	if err != nil {
+		return diag.Errorf("failed to marshal general security settings during Delete")
+	}
 
-	err := sendConfigurationPatch([]byte(content), m)
+	err = sendConfigurationPatch(ctx, content, m)
 	if err != nil {
-		return diag.Errorf("failed to send PATCH request to Artifactory during Delete")
+		return diag.Errorf("failed to send PATCH request to Artifactory during Delete: %s", err)
 	}
 
 	return nil
 }
 
-func unpackGeneralSecurity(s *schema.ResourceData) *GeneralSecurity {
-	d := &ResourceData{s}
-	security := *new(GeneralSecurity)
+// unpackGeneralSecurity leaves out every setting that is neither configured nor in the state yet, so the patch
+// doesn't reset what an admin set in the UI
+func unpackGeneralSecurity(d *schema.ResourceData) *generalSecurityPatch {
+	settings := generalSettingsPatch{
+		AnonAccessEnabled:         d.Get("enable_anonymous_access").(bool),
+		HideUnauthorizedResources: optionalBool(d, "hide_unauthorized_resources"),
+	}
 
-	settings := GeneralSettings{
-		AnonAccessEnabled: d.getBool("enable_anonymous_access", false),
+	password := passwordSettingsPatch{EncryptionPolicy: optionalString(d, "password_encryption_policy")}
+	expiration := passwordExpirationPolicyPatch{
+		Enabled:        optionalBool(d, "password_expiration_enabled"),
+		PasswordMaxAge: optionalInt(d, "password_max_age_days"),
+		NotifyByEmail:  optionalBool(d, "password_expiration_notify_by_email"),
+	}
+	if expiration != (passwordExpirationPolicyPatch{}) {
+		password.ExpirationPolicy = &expiration
+	}
+	if password != (passwordSettingsPatch{}) {
+		settings.PasswordSettings = &password
 	}
 
-	security.GeneralSettings = settings
-	return &security
+	userLock := userLockPolicyPatch{
+		Enabled:       optionalBool(d, "user_lock_enabled"),
+		LoginAttempts: optionalInt(d, "user_lock_login_attempts"),
+	}
+	if userLock != (userLockPolicyPatch{}) {
+		settings.UserLockPolicy = &userLock
+	}
+
+	apiKey := apiKeySettingsPatch{
+		CreationEnabled: optionalBool(d, "api_key_creation_enabled"),
+		UsageEnabled:    optionalBool(d, "api_key_usage_enabled"),
+	}
+	if apiKey != (apiKeySettingsPatch{}) {
+		settings.ApiKeySettings = &apiKey
+	}
+
+	return &generalSecurityPatch{Security: settings}
+}
+
+func optionalBool(d *schema.ResourceData, key string) *bool {
+	if v, ok := d.GetOkExists(key); ok {
+		b := v.(bool)
+		return &b
+	}
+	return nil
+}
+
+func optionalInt(d *schema.ResourceData, key string) *int {
+	if v, ok := d.GetOkExists(key); ok {
+		i := v.(int)
+		return &i
+	}
+	return nil
+}
+
+func optionalString(d *schema.ResourceData, key string) *string {
+	if v, ok := d.GetOkExists(key); ok {
+		str := v.(string)
+		return &str
+	}
+	return nil
 }
 
 func packGeneralSecurity(s *GeneralSecurity, d *schema.ResourceData) diag.Diagnostics {
 	setValue := mkLens(d)
 
-	errors := setValue("enable_anonymous_access", s.GeneralSettings.AnonAccessEnabled)
+	setValue("enable_anonymous_access", s.GeneralSettings.AnonAccessEnabled)
+	setValue("hide_unauthorized_resources", s.HideUnauthorizedResources)
+	setValue("password_encryption_policy", s.PasswordSettings.EncryptionPolicy)
+	setValue("password_expiration_enabled", s.PasswordSettings.ExpirationPolicy.Enabled)
+	setValue("password_max_age_days", s.PasswordSettings.ExpirationPolicy.PasswordMaxAge)
+	setValue("password_expiration_notify_by_email", s.PasswordSettings.ExpirationPolicy.NotifyByEmail)
+	setValue("user_lock_enabled", s.UserLockPolicy.Enabled)
+	setValue("user_lock_login_attempts", s.UserLockPolicy.LoginAttempts)
+	setValue("api_key_creation_enabled", s.ApiKeySettings.CreationEnabled)
+	errors := setValue("api_key_usage_enabled", s.ApiKeySettings.UsageEnabled)
 
 	if errors != nil && len(errors) > 0 {
 		return diag.Errorf("failed to pack general security settings %q", errors)
@@ -1,18 +1,26 @@
 package artifactory
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
-	"github.com/go-resty/resty/v2"
-
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
 )
 
 const GeneralSecurityTemplateFull = `
 resource "artifactory_general_security" "security" {
-	enable_anonymous_access = true
+	enable_anonymous_access     = true
+	hide_unauthorized_resources = true
+	password_encryption_policy  = "required"
+	password_expiration_enabled = true
+	password_max_age_days       = 90
+	user_lock_enabled           = true
+	user_lock_login_attempts    = 3
+	api_key_creation_enabled    = false
 }`
 
 func TestAccGeneralSecurity_full(t *testing.T) {
@@ -25,6 +33,11 @@ func TestAccGeneralSecurity_full(t *testing.T) {
 				Config: GeneralSecurityTemplateFull,
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("artifactory_general_security.security", "enable_anonymous_access", "true"),
+					resource.TestCheckResourceAttr("artifactory_general_security.security", "password_encryption_policy", "required"),
+					resource.TestCheckResourceAttr("artifactory_general_security.security", "password_max_age_days", "90"),
+					resource.TestCheckResourceAttr("artifactory_general_security.security", "user_lock_login_attempts", "3"),
+					resource.TestCheckResourceAttr("artifactory_general_security.security", "api_key_creation_enabled", "false"),
+					resource.TestCheckResourceAttr("artifactory_general_security.security", "api_key_usage_enabled", "true"),
 				),
 			},
 		},
@@ -34,7 +47,7 @@ func TestAccGeneralSecurity_full(t *testing.T) {
 func testAccGeneralSecurityDestroy(id string) func(*terraform.State) error {
 	return func(s *terraform.State) error {
 		provider, _ := testAccProviders["artifactory"]()
-		client := provider.Meta().(*resty.Client)
+		client := provider.Meta().(*ProviderMetadata).Client
 
 		_, ok := s.RootModule().Resources[id]
 		if !ok {
@@ -53,3 +66,48 @@ func testAccGeneralSecurityDestroy(id string) func(*terraform.State) error {
 		return nil
 	}
 }
+
+func TestGeneralSecurityDrift(t *testing.T) {
+	meta, _ := newFakeMeta(t)
+	ctx := context.Background()
+	res := resourceArtifactoryGeneralSecurity()
+
+	// set in the UI before terraform took over
+	if err := sendConfigurationPatch(ctx, []byte("security:\n  passwordSettings:\n    expirationPolicy:\n      passwordMaxAge: 30\n"), meta); err != nil {
+		t.Fatal(err)
+	}
+
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{
+		"user_lock_enabled":        true,
+		"user_lock_login_attempts": 3,
+		"api_key_creation_enabled": false,
+	})
+	if diags := res.CreateContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("create failed: %v", diags)
+	}
+	assert.Equal(t, 3, d.Get("user_lock_login_attempts"))
+	assert.Equal(t, false, d.Get("api_key_creation_enabled"))
+	assert.Equal(t, 30, d.Get("password_max_age_days"), "what isn't configured is left as it was")
+
+	// changed in the UI
+	patch := "security:\n  userLockPolicy:\n    loginAttempts: 10\n  passwordSettings:\n    encryptionPolicy: required\n"
+	if err := sendConfigurationPatch(ctx, []byte(patch), meta); err != nil {
+		t.Fatal(err)
+	}
+	if diags := res.ReadContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("read failed: %v", diags)
+	}
+	assert.Equal(t, 10, d.Get("user_lock_login_attempts"))
+	assert.Equal(t, "required", d.Get("password_encryption_policy"))
+	assert.Equal(t, true, d.Get("user_lock_enabled"), "a patch only changes what it names")
+
+	if diags := res.DeleteContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("delete failed: %v", diags)
+	}
+	if diags := res.ReadContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("read failed: %v", diags)
+	}
+	assert.Equal(t, false, d.Get("user_lock_enabled"), "delete goes back to the defaults")
+	assert.Equal(t, 5, d.Get("user_lock_login_attempts"))
+	assert.Equal(t, true, d.Get("api_key_creation_enabled"))
+}
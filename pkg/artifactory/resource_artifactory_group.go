@@ -1,9 +1,11 @@
 package artifactory
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -14,14 +16,14 @@ const groupsEndpoint = "artifactory/api/security/groups/"
 
 func resourceArtifactoryGroup() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceGroupCreate,
-		Read:   resourceGroupRead,
-		Update: resourceGroupUpdate,
-		Delete: resourceGroupDelete,
-		Exists: resourceGroupExists,
+		CreateContext: resourceGroupCreate,
+		ReadContext:   resourceGroupRead,
+		UpdateContext: resourceGroupUpdate,
+		DeleteContext: resourceGroupDelete,
+		Timeouts:      defaultResourceTimeouts(),
 
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -96,20 +98,20 @@ func groupParams(s *schema.ResourceData) (Group, bool, error) {
 	return group, includeUsers, nil
 }
 
-func resourceGroupCreate(d *schema.ResourceData, m interface{}) error {
+func resourceGroupCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	group, _, err := groupParams(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	_, err = m.(*resty.Client).R().SetBody(group).Put(groupsEndpoint + group.Name)
+	_, err = m.(*ProviderMetadata).Client.R().SetContext(ctx).SetBody(group).Put(groupsEndpoint + group.Name)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(group.Name)
-	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-		exists, err := resourceGroupExists(d, m)
+	return diag.FromErr(resource.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		exists, err := groupExists(ctx, m.(*ProviderMetadata).Client, d.Id())
 		if err != nil {
 			return resource.NonRetryableError(fmt.Errorf("error describing group: %s", err))
 		}
@@ -119,10 +121,10 @@ func resourceGroupCreate(d *schema.ResourceData, m interface{}) error {
 		}
 
 		return nil
-	})
+	}))
 }
 
-func resourceGroupGet(d *schema.ResourceData, m interface{}) (*Group, error) {
+func resourceGroupGet(ctx context.Context, d *schema.ResourceData, m interface{}) (*Group, error) {
 	_, includeUsers, err := groupParams(d)
 	if err != nil {
 		return nil, err
@@ -130,22 +132,25 @@ func resourceGroupGet(d *schema.ResourceData, m interface{}) (*Group, error) {
 
 	group := Group{}
 	url := fmt.Sprintf("%s%s?includeUsers=%t", groupsEndpoint, d.Id(), includeUsers)
-	_, err = m.(*resty.Client).R().SetResult(&group).Get(url)
+	_, err = m.(*ProviderMetadata).Client.R().SetContext(ctx).SetResult(&group).Get(url)
 	return &group, err
 }
 
-func resourceGroupRead(d *schema.ResourceData, m interface{}) error {
-	group, err := resourceGroupGet(d, m)
+func resourceGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	group, err := resourceGroupGet(ctx, d, m)
 	if err != nil {
 		// If we 404 it is likely the resources was externally deleted
 		// If the ID is updated to blank, this tells Terraform the resource no longer exist
-		if group == nil {
+		if IsNotFound(err) {
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diag.FromErr(err)
 	}
+	return diag.FromErr(packGroup(group, d))
+}
 
+func packGroup(group *Group, d *schema.ResourceData) error {
 	setValue := mkLens(d)
 	setValue("name", group.Name)
 	setValue("description", group.Description)
@@ -160,10 +165,10 @@ func resourceGroupRead(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
-func resourceGroupUpdate(d *schema.ResourceData, m interface{}) error {
+func resourceGroupUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	group, includeUsers, err := groupParams(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Create and Update uses same endpoint, create checks for ReplaceIfExists and then uses put
@@ -172,32 +177,34 @@ func resourceGroupUpdate(d *schema.ResourceData, m interface{}) error {
 	// this results in a group where users are not managed by artifactory if users_names is not set.
 
 	if includeUsers {
-		_, err := m.(*resty.Client).R().SetBody(group).Put(groupsEndpoint + d.Id())
+		_, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).SetBody(group).Put(groupsEndpoint + d.Id())
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	} else {
-		_, err = m.(*resty.Client).R().SetBody(group).Post(groupsEndpoint + d.Id())
+		_, err = m.(*ProviderMetadata).Client.R().SetContext(ctx).SetBody(group).Post(groupsEndpoint + d.Id())
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	d.SetId(group.Name)
-	return resourceGroupRead(d, m)
-}
-
-func resourceGroupDelete(d *schema.ResourceData, m interface{}) error {
-	_, err := m.(*resty.Client).R().Delete(groupsEndpoint + d.Id())
-	return err
+	return resourceGroupRead(ctx, d, m)
 }
 
-func resourceGroupExists(d *schema.ResourceData, m interface{}) (bool, error) {
-	return groupExists(m.(*resty.Client), d.Id())
+func resourceGroupDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	_, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).Delete(groupsEndpoint + d.Id())
+	if IsNotFound(err) {
+		return nil
+	}
+	return diag.FromErr(err)
 }
 
-func groupExists(client *resty.Client, groupName string) (bool, error) {
-	_, err := client.R().Head(groupsEndpoint + groupName)
+func groupExists(ctx context.Context, client *resty.Client, groupName string) (bool, error) {
+	_, err := client.R().SetContext(ctx).Head(groupsEndpoint + groupName)
+	if IsNotFound(err) {
+		return false, nil
+	}
 	return err == nil, err
 }
 
@@ -0,0 +1,145 @@
+package artifactory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+)
+
+// resourceArtifactoryGroupMembers adds users to a group without owning the group's membership, unlike users_names on
+// artifactory_group. Users it doesn't know about are left alone, so several of them can share a group
+func resourceArtifactoryGroupMembers() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceGroupMembersCreate,
+		ReadContext:   resourceGroupMembersRead,
+		UpdateContext: resourceGroupMembersUpdate,
+		DeleteContext: resourceGroupMembersDelete,
+		Timeouts:      defaultResourceTimeouts(),
+
+		Schema: map[string]*schema.Schema{
+			"group_name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"members": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+		},
+	}
+}
+
+func resourceGroupMembersCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	group := d.Get("group_name").(string)
+	for _, user := range castToStringArr(d.Get("members").(*schema.Set).List()) {
+		if err := changeUserGroups(ctx, m, user, []string{group}, nil); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	d.SetId(group)
+	return resourceGroupMembersRead(ctx, d, m)
+}
+
+func resourceGroupMembersRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	group := Group{}
+	url := fmt.Sprintf("%s%s?includeUsers=true", groupsEndpoint, d.Id())
+	if _, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).SetResult(&group).Get(url); err != nil {
+		if IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	// only the members we added are ours. One removed out of band drops out and gets added back
+	owned := d.Get("members").(*schema.Set)
+	members := schema.NewSet(schema.HashString, nil)
+	for _, user := range group.UsersNames {
+		if owned.Contains(user) {
+			members.Add(user)
+		}
+	}
+
+	setValue := mkLens(d)
+	setValue("group_name", d.Id())
+	errors := setValue("members", members)
+	if errors != nil && len(errors) > 0 {
+		return diag.Errorf("failed saving state for group members %q", errors)
+	}
+	return nil
+}
+
+func resourceGroupMembersUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	group := d.Get("group_name").(string)
+	old, new := d.GetChange("members")
+	for _, user := range castToStringArr(new.(*schema.Set).Difference(old.(*schema.Set)).List()) {
+		if err := changeUserGroups(ctx, m, user, []string{group}, nil); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	for _, user := range castToStringArr(old.(*schema.Set).Difference(new.(*schema.Set)).List()) {
+		if err := changeUserGroups(ctx, m, user, nil, []string{group}); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return resourceGroupMembersRead(ctx, d, m)
+}
+
+func resourceGroupMembersDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	group := d.Get("group_name").(string)
+	for _, user := range castToStringArr(d.Get("members").(*schema.Set).List()) {
+		if err := changeUserGroups(ctx, m, user, nil, []string{group}); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return nil
+}
+
+// changeUserGroups adds and removes groups of a user, keeping the rest of them. The users api is the only one that
+// changes membership without replacing the whole group, but it takes the user's full list of groups - hence the
+// lock around reading and writing it back. A user that is gone has nothing left to remove
+func changeUserGroups(ctx context.Context, m interface{}, name string, add, remove []string) error {
+	unlock, err := lockMembership(ctx, m)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	client := m.(*ProviderMetadata).Client
+	user := services.User{}
+	if _, err := client.R().SetContext(ctx).SetResult(&user).Get(usersEndpoint + name); err != nil {
+		if IsNotFound(err) && len(add) == 0 {
+			return nil
+		}
+		return err
+	}
+
+	current := schema.NewSet(schema.HashString, castToInterfaceArr(user.Groups))
+	groups := schema.NewSet(schema.HashString, castToInterfaceArr(user.Groups))
+	for _, group := range add {
+		groups.Add(group)
+	}
+	for _, group := range remove {
+		groups.Remove(group)
+	}
+	if groups.Equal(current) {
+		return nil
+	}
+	// not services.User, its omitempty would keep the last group from being removed
+	body := struct {
+		Groups []string `json:"groups"`
+	}{Groups: castToStringArr(groups.List())}
+	if body.Groups == nil {
+		body.Groups = []string{}
+	}
+	_, err = client.R().SetContext(ctx).SetBody(body).Post(usersEndpoint + name)
+	return err
+}
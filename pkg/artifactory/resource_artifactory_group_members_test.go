@@ -0,0 +1,108 @@
+package artifactory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccGroupMembers(t *testing.T) {
+	_, fqrn, name := mkNames("test-group-members", "artifactory_group_members")
+	temp := `
+		resource "artifactory_group" "{{ .name }}" {
+			name = "{{ .name }}"
+		}
+
+		resource "artifactory_user" "{{ .name }}" {
+			name     = "{{ .name }}"
+			email    = "{{ .name }}@example.com"
+			password = "Passw0rd!"
+			lifecycle {
+				ignore_changes = [groups]
+			}
+		}
+
+		resource "artifactory_group_members" "{{ .name }}" {
+			group_name = artifactory_group.{{ .name }}.name
+			members    = [artifactory_user.{{ .name }}.name]
+		}
+	`
+	config := executeTemplate(name, temp, map[string]string{"name": name})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		CheckDestroy:      testAccCheckGroupDestroy("artifactory_group." + name),
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fqrn, "group_name", name),
+					resource.TestCheckResourceAttr(fqrn, "members.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestGroupMembersLeaveOthersAlone(t *testing.T) {
+	meta, client := newFakeMeta(t)
+	ctx := context.Background()
+
+	for _, group := range []string{"shared", "other"} {
+		if _, err := client.R().SetBody(Group{Name: group}).Put(groupsEndpoint + group); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, user := range []string{"alice", "bob", "carol"} {
+		body := services.User{Name: user, Email: user + "@example.com", Password: "Passw0rd!"}
+		if user == "carol" {
+			body.Groups = []string{"shared"}
+		}
+		if _, err := client.R().SetBody(body).Put(usersEndpoint + user); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	members := resourceArtifactoryGroupMembers()
+	md := schema.TestResourceDataRaw(t, members.Schema, map[string]interface{}{
+		"group_name": "shared",
+		"members":    []interface{}{"alice", "bob"},
+	})
+	if diags := members.CreateContext(ctx, md, meta); diags.HasError() {
+		t.Fatalf("create failed: %v", diags)
+	}
+	userGroups := resourceArtifactoryUserGroups()
+	ud := schema.TestResourceDataRaw(t, userGroups.Schema, map[string]interface{}{
+		"username": "alice",
+		"groups":   []interface{}{"other"},
+	})
+	if diags := userGroups.CreateContext(ctx, ud, meta); diags.HasError() {
+		t.Fatalf("create failed: %v", diags)
+	}
+	// carol was there first and isn't ours
+	assert.Equal(t, 2, md.Get("members").(*schema.Set).Len())
+
+	if diags := members.DeleteContext(ctx, md, meta); diags.HasError() {
+		t.Fatalf("delete failed: %v", diags)
+	}
+	groupsOf := func(name string) []string {
+		user := services.User{}
+		if _, err := client.R().SetResult(&user).Get(usersEndpoint + name); err != nil {
+			t.Fatal(err)
+		}
+		return user.Groups
+	}
+	assert.Equal(t, []string{"shared"}, groupsOf("carol"))
+	assert.Equal(t, []string{"other"}, groupsOf("alice"))
+	assert.Empty(t, groupsOf("bob"))
+
+	if diags := userGroups.DeleteContext(ctx, ud, meta); diags.HasError() {
+		t.Fatalf("delete failed: %v", diags)
+	}
+	assert.Empty(t, groupsOf("alice"))
+}
@@ -5,7 +5,6 @@ import (
 	"net/http"
 	"testing"
 
-	"github.com/go-resty/resty/v2"
 	"github.com/jfrog/jfrog-client-go/artifactory/services"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -255,7 +254,7 @@ func TestAccGroup_unmanagedmembers(t *testing.T) {
 func testAccCheckGroupDestroy(id string) func(*terraform.State) error {
 	return func(s *terraform.State) error {
 		provider, _ := testAccProviders["artifactory"]()
-		client := provider.Meta().(*resty.Client)
+		client := provider.Meta().(*ProviderMetadata).Client
 
 		rs, ok := s.RootModule().Resources[id]
 		if !ok {
@@ -277,7 +276,7 @@ func testAccCheckGroupDestroy(id string) func(*terraform.State) error {
 func testAccDirectCheckGroupMembership(id string, expectedCount int) func(*terraform.State) error {
 	return func(s *terraform.State) error {
 		provider, _ := testAccProviders["artifactory"]()
-		client := provider.Meta().(*resty.Client)
+		client := provider.Meta().(*ProviderMetadata).Client
 
 		rs, ok := s.RootModule().Resources[id]
 		if !ok {
@@ -0,0 +1,148 @@
+package artifactory
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v2"
+)
+
+type HttpSsoSecurity struct {
+	HttpSso HttpSsoSettingsWrapper `yaml:"security"`
+}
+
+type HttpSsoSettingsWrapper struct {
+	Settings HttpSsoSettings `yaml:"httpSsoSettings"`
+}
+
+type HttpSsoSettings struct {
+	HttpSsoProxied            bool   `yaml:"httpSsoProxied" xml:"httpSsoProxied"`
+	RemoteUserRequestVariable string `yaml:"remoteUserRequestVariable" xml:"remoteUserRequestVariable"`
+	NoAutoUserCreation        bool   `yaml:"noAutoUserCreation" xml:"noAutoUserCreation"`
+	AllowUserToAccessProfile  bool   `yaml:"allowUserToAccessProfile" xml:"allowUserToAccessProfile"`
+	SyncLdapGroups            bool   `yaml:"syncLdapGroups" xml:"syncLdapGroups"`
+}
+
+// HttpSsoConfiguration is the part of artifactory.config.xml with the http sso settings. They're nil once deleted
+type HttpSsoConfiguration struct {
+	Settings *HttpSsoSettings `xml:"security>httpSsoSettings"`
+}
+
+func resourceArtifactoryHttpSsoSettings() *schema.Resource {
+	return &schema.Resource{
+		UpdateContext: resourceHttpSsoSettingsUpdate,
+		CreateContext: resourceHttpSsoSettingsUpdate,
+		DeleteContext: resourceHttpSsoSettingsDelete,
+		ReadContext:   resourceHttpSsoSettingsRead,
+		Timeouts:      defaultResourceTimeouts(),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"proxied": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Trust the user the proxy in front of artifactory authenticated",
+			},
+			"remote_user_request_variable": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "REMOTE_USER",
+				Description: "The header or request variable the proxy puts the user in",
+			},
+			"no_auto_user_creation": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"allow_user_to_access_profile": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"sync_ldap_groups": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Take the groups of the user from ldap",
+			},
+		},
+	}
+}
+
+func resourceHttpSsoSettingsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	config := HttpSsoConfiguration{}
+	if err := getConfiguration(ctx, m, &config); err != nil {
+		return diag.Errorf("failed to retrieve the http sso settings during Read: %s", err)
+	}
+	if config.Settings == nil {
+		d.SetId("")
+		return nil
+	}
+	return packHttpSsoSecurity(&HttpSsoSecurity{HttpSsoSettingsWrapper{*config.Settings}}, d)
+}
+
+func resourceHttpSsoSettingsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	unpacked := unpackHttpSsoSecurity(d)
+	content, err := yaml.Marshal(&unpacked)
+
+	if err != nil {
+		return diag.Errorf("failed to marshal http sso settings during Update")
+	}
+
+	err = sendConfigurationPatch(ctx, content, m)
+	if err != nil {
+		return diag.Errorf("failed to send PATCH request to Artifactory during Update: %s", err)
+	}
+
+	// we should only have one http sso settings resource, using same id
+	d.SetId("http_sso_settings")
+	return resourceHttpSsoSettingsRead(ctx, d, m)
+}
+
+func resourceHttpSsoSettingsDelete(ctx context.Context, _ *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var content = `
+security:
+  httpSsoSettings: ~
+`
+
+	err := sendConfigurationPatch(ctx, []byte(content), m)
+	if err != nil {
+		return diag.Errorf("failed to send PATCH request to Artifactory during Delete: %s", err)
+	}
+	return nil
+}
+
+func unpackHttpSsoSecurity(s *schema.ResourceData) *HttpSsoSecurity {
+	d := &ResourceData{s}
+	security := new(HttpSsoSecurity)
+
+	security.HttpSso.Settings = HttpSsoSettings{
+		HttpSsoProxied:            d.getBool("proxied", false),
+		RemoteUserRequestVariable: d.getString("remote_user_request_variable", false),
+		NoAutoUserCreation:        d.getBool("no_auto_user_creation", false),
+		AllowUserToAccessProfile:  d.getBool("allow_user_to_access_profile", false),
+		SyncLdapGroups:            d.getBool("sync_ldap_groups", false),
+	}
+	return security
+}
+
+func packHttpSsoSecurity(s *HttpSsoSecurity, d *schema.ResourceData) diag.Diagnostics {
+	setValue := mkLens(d)
+	settings := s.HttpSso.Settings
+
+	setValue("proxied", settings.HttpSsoProxied)
+	setValue("remote_user_request_variable", settings.RemoteUserRequestVariable)
+	setValue("no_auto_user_creation", settings.NoAutoUserCreation)
+	setValue("allow_user_to_access_profile", settings.AllowUserToAccessProfile)
+	errors := setValue("sync_ldap_groups", settings.SyncLdapGroups)
+
+	if errors != nil && len(errors) > 0 {
+		return diag.Errorf("failed to pack http sso settings %q", errors)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package artifactory
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const HttpSsoSettingsTemplateFull = `
+resource "artifactory_http_sso_settings" "sso" {
+	proxied                      = true
+	remote_user_request_variable = "X-Remote-User"
+	allow_user_to_access_profile = true
+}`
+
+func TestAccHttpSsoSettings_full(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		CheckDestroy:      testAccHttpSsoSettingsDestroy("artifactory_http_sso_settings.sso"),
+		ProviderFactories: testAccProviders,
+
+		Steps: []resource.TestStep{
+			{
+				Config: HttpSsoSettingsTemplateFull,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("artifactory_http_sso_settings.sso", "proxied", "true"),
+					resource.TestCheckResourceAttr("artifactory_http_sso_settings.sso", "remote_user_request_variable", "X-Remote-User"),
+					resource.TestCheckResourceAttr("artifactory_http_sso_settings.sso", "no_auto_user_creation", "false"),
+					resource.TestCheckResourceAttr("artifactory_http_sso_settings.sso", "allow_user_to_access_profile", "true"),
+				),
+			},
+		},
+	})
+}
+
+func testAccHttpSsoSettingsDestroy(id string) func(*terraform.State) error {
+	return func(s *terraform.State) error {
+		provider, _ := testAccProviders["artifactory"]()
+
+		_, ok := s.RootModule().Resources[id]
+		if !ok {
+			return fmt.Errorf("error: resource id [%s] not found", id)
+		}
+		config := HttpSsoConfiguration{}
+		if err := getConfiguration(context.Background(), provider.Meta(), &config); err != nil {
+			return err
+		}
+		if config.Settings != nil && config.Settings.HttpSsoProxied {
+			return fmt.Errorf("error: http sso is still enabled")
+		}
+		return nil
+	}
+}
+
+func TestHttpSsoSettingsDeletedOutsideOfTerraform(t *testing.T) {
+	meta, _ := newFakeMeta(t)
+	ctx := context.Background()
+	res := resourceArtifactoryHttpSsoSettings()
+
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{
+		"proxied":                      true,
+		"remote_user_request_variable": "X-Remote-User",
+	})
+	if diags := res.CreateContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("create failed: %v", diags)
+	}
+	if d.Id() == "" || d.Get("proxied") != true {
+		t.Fatalf("expected the settings to be read back, got %q %v", d.Id(), d.Get("proxied"))
+	}
+
+	if diags := res.DeleteContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("delete failed: %v", diags)
+	}
+	if diags := res.ReadContext(ctx, d, meta); diags.HasError() || d.Id() != "" {
+		t.Errorf("expected deleted settings to clear the id, got %q %v", d.Id(), diags)
+	}
+}
@@ -30,6 +30,7 @@ func resourceArtifactoryKeyPair() *schema.Resource {
 		CreateContext: createKeyPair,
 		DeleteContext: rmKeyPair,
 		ReadContext:   readKeyPair,
+		Timeouts:      defaultResourceTimeouts(),
 
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
@@ -174,10 +175,10 @@ func unpackKeyPair(s *schema.ResourceData) (interface{}, string, error) {
 	return &result, result.PairName, nil
 }
 
-func createKeyPair(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func createKeyPair(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	keyPair, key, _ := unpackKeyPair(d)
 
-	_, err := m.(*resty.Client).R().SetBody(keyPair).Post(keypairEndPoint)
+	_, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).SetBody(keyPair).Post(keypairEndPoint)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -189,11 +190,15 @@ func createKeyPair(_ context.Context, d *schema.ResourceData, m interface{}) dia
 	return nil
 }
 
-func readKeyPair(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+func readKeyPair(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	data := KeyPairPayLoad{}
-	_, err := meta.(*resty.Client).R().SetResult(&data).Get(keypairEndPoint + d.Id())
+	_, err := meta.(*ProviderMetadata).Client.R().SetContext(ctx).SetResult(&data).Get(keypairEndPoint + d.Id())
 	if err != nil {
+		if IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
 		return diag.FromErr(err)
 	}
 	err = universalPack(data, d)
@@ -202,9 +207,9 @@ func readKeyPair(_ context.Context, d *schema.ResourceData, meta interface{}) di
 	}
 	return nil
 }
-func rmKeyPair(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	_, err := m.(*resty.Client).R().Delete(keypairEndPoint + d.Id())
-	if err != nil {
+func rmKeyPair(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	_, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).Delete(keypairEndPoint + d.Id())
+	if err != nil && !IsNotFound(err) {
 		return diag.FromErr(err)
 	}
 	return nil
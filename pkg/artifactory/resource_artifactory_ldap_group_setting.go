@@ -0,0 +1,172 @@
+package artifactory
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"gopkg.in/yaml.v2"
+)
+
+// LdapGroupSecurity is the yaml patch for a single ldap group setting, keyed by its name like the ldap settings
+type LdapGroupSecurity struct {
+	LdapGroups LdapGroupSettingsWrapper `yaml:"security"`
+}
+
+type LdapGroupSettingsWrapper struct {
+	Settings map[string]*LdapGroupSetting `yaml:"ldapGroupSettings"`
+}
+
+type LdapGroupSetting struct {
+	Name                 string `yaml:"-" xml:"name"`
+	EnabledLdap          string `yaml:"enabledLdap" xml:"enabledLdap"`
+	GroupBaseDn          string `yaml:"groupBaseDn" xml:"groupBaseDn"`
+	GroupNameAttribute   string `yaml:"groupNameAttribute" xml:"groupNameAttribute"`
+	GroupMemberAttribute string `yaml:"groupMemberAttribute" xml:"groupMemberAttribute"`
+	SubTree              bool   `yaml:"subTree" xml:"subTree"`
+	Filter               string `yaml:"filter" xml:"filter"`
+	DescriptionAttribute string `yaml:"descriptionAttribute" xml:"descriptionAttribute"`
+	Strategy             string `yaml:"strategy" xml:"strategy"`
+}
+
+func resourceArtifactoryLdapGroupSetting() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceLdapGroupSettingUpdate,
+		ReadContext:   resourceLdapGroupSettingRead,
+		UpdateContext: resourceLdapGroupSettingUpdate,
+		DeleteContext: resourceLdapGroupSettingDelete,
+		Timeouts:      defaultResourceTimeouts(),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"ldap_setting_key": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The key of the artifactory_ldap_setting the groups are synchronized with",
+			},
+			"strategy": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice([]string{"static", "dynamic", "hierarchical"}, false),
+				Description:  "static: the group lists its members. dynamic: the user lists its groups. hierarchical: the user's DN has its groups",
+			},
+			"group_base_dn": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Where to search for groups from, relative to the url of the ldap setting",
+			},
+			"group_name_attribute": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "cn",
+			},
+			"group_member_attribute": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "uniqueMember",
+				Description: "The attribute of a group with its members for static, of a user with its groups for dynamic",
+			},
+			"filter": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "(objectClass=groupOfNames)",
+			},
+			"sub_tree": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"description_attribute": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "description",
+			},
+		},
+	}
+}
+
+func unpackLdapGroupSetting(s *schema.ResourceData) *LdapGroupSetting {
+	d := &ResourceData{s}
+	return &LdapGroupSetting{
+		Name:                 d.getString("name", false),
+		EnabledLdap:          d.getString("ldap_setting_key", false),
+		GroupBaseDn:          d.getString("group_base_dn", false),
+		GroupNameAttribute:   d.getString("group_name_attribute", false),
+		GroupMemberAttribute: d.getString("group_member_attribute", false),
+		SubTree:              d.getBool("sub_tree", false),
+		Filter:               d.getString("filter", false),
+		DescriptionAttribute: d.getString("description_attribute", false),
+		Strategy:             d.getString("strategy", false),
+	}
+}
+
+func packLdapGroupSetting(setting *LdapGroupSetting, d *schema.ResourceData) diag.Diagnostics {
+	setValue := mkLens(d)
+
+	setValue("name", setting.Name)
+	setValue("ldap_setting_key", setting.EnabledLdap)
+	setValue("group_base_dn", setting.GroupBaseDn)
+	setValue("group_name_attribute", setting.GroupNameAttribute)
+	setValue("group_member_attribute", setting.GroupMemberAttribute)
+	setValue("sub_tree", setting.SubTree)
+	setValue("filter", setting.Filter)
+	setValue("description_attribute", setting.DescriptionAttribute)
+	errors := setValue("strategy", setting.Strategy)
+
+	if errors != nil && len(errors) > 0 {
+		return diag.Errorf("failed to pack ldap group setting %q", errors)
+	}
+	return nil
+}
+
+func resourceLdapGroupSettingRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	config := LdapConfiguration{}
+	if err := getConfiguration(ctx, m, &config); err != nil {
+		return diag.Errorf("failed to retrieve the ldap group settings during Read: %s", err)
+	}
+	for _, setting := range config.GroupSettings {
+		if setting.Name == d.Id() {
+			return packLdapGroupSetting(&setting, d)
+		}
+	}
+	d.SetId("")
+	return nil
+}
+
+func resourceLdapGroupSettingUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	setting := unpackLdapGroupSetting(d)
+	content, err := yaml.Marshal(&LdapGroupSecurity{LdapGroupSettingsWrapper{map[string]*LdapGroupSetting{setting.Name: setting}}})
+	if err != nil {
+		return diag.Errorf("failed to marshal ldap group setting during Update")
+	}
+
+	err = sendConfigurationPatch(ctx, content, m)
+	if err != nil {
+		return diag.Errorf("failed to send PATCH request to Artifactory during Update: %s", err)
+	}
+
+	d.SetId(setting.Name)
+	return resourceLdapGroupSettingRead(ctx, d, m)
+}
+
+func resourceLdapGroupSettingDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	content, err := yaml.Marshal(&LdapGroupSecurity{LdapGroupSettingsWrapper{map[string]*LdapGroupSetting{d.Id(): nil}}})
+	if err != nil {
+		return diag.Errorf("failed to marshal ldap group setting during Delete")
+	}
+
+	err = sendConfigurationPatch(ctx, content, m)
+	if err != nil {
+		return diag.Errorf("failed to send PATCH request to Artifactory during Delete: %s", err)
+	}
+	return nil
+}
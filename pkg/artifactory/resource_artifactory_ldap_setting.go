@@ -0,0 +1,225 @@
+package artifactory
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v2"
+)
+
+// LdapSecurity is the yaml patch for a single ldap setting. ldapSettings is a map keyed by the setting's key, so a
+// patch only ever touches the one setting it names
+type LdapSecurity struct {
+	Ldap LdapSettingsWrapper `yaml:"security"`
+}
+
+type LdapSettingsWrapper struct {
+	Settings map[string]*LdapSetting `yaml:"ldapSettings"`
+}
+
+type LdapSetting struct {
+	Key                      string     `yaml:"-" xml:"key"`
+	Enabled                  bool       `yaml:"enabled" xml:"enabled"`
+	LdapUrl                  string     `yaml:"ldapUrl" xml:"ldapUrl"`
+	UserDnPattern            string     `yaml:"userDnPattern" xml:"userDnPattern"`
+	EmailAttribute           string     `yaml:"emailAttribute" xml:"emailAttribute"`
+	AutoCreateUser           bool       `yaml:"autoCreateUser" xml:"autoCreateUser"`
+	LdapPoisoningProtection  bool       `yaml:"ldapPoisoningProtection" xml:"ldapPoisoningProtection"`
+	AllowUserToAccessProfile bool       `yaml:"allowUserToAccessProfile" xml:"allowUserToAccessProfile"`
+	PagingSupportEnabled     bool       `yaml:"pagingSupportEnabled" xml:"pagingSupportEnabled"`
+	Search                   LdapSearch `yaml:"search" xml:"search"`
+}
+
+type LdapSearch struct {
+	SearchFilter    string `yaml:"searchFilter" xml:"searchFilter"`
+	SearchBase      string `yaml:"searchBase" xml:"searchBase"`
+	SearchSubTree   bool   `yaml:"searchSubTree" xml:"searchSubTree"`
+	ManagerDn       string `yaml:"managerDn" xml:"managerDn"`
+	ManagerPassword string `yaml:"managerPassword,omitempty" xml:"managerPassword"`
+}
+
+// LdapConfiguration is the part of artifactory.config.xml with the ldap settings and ldap group settings
+type LdapConfiguration struct {
+	Settings      []LdapSetting      `xml:"security>ldapSettings>ldapSetting"`
+	GroupSettings []LdapGroupSetting `xml:"security>ldapGroupSettings>ldapGroupSetting"`
+}
+
+func resourceArtifactoryLdapSetting() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceLdapSettingUpdate,
+		ReadContext:   resourceLdapSettingRead,
+		UpdateContext: resourceLdapSettingUpdate,
+		DeleteContext: resourceLdapSettingDelete,
+		Timeouts:      defaultResourceTimeouts(),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"key": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"url": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "eg ldap://ldap.example.com:389/dc=example,dc=com",
+			},
+			"user_dn_pattern": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "eg uid={0},ou=People. Either this or search_filter finds the user",
+			},
+			"email_attribute": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "mail",
+			},
+			"auto_create_user": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"ldap_poisoning_protection": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"allow_user_to_access_profile": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"paging_support_enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"search_filter": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "eg (uid={0})",
+			},
+			"search_base": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Where to search from, relative to the url. Several are separated with |",
+			},
+			"search_sub_tree": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"manager_dn": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"manager_password": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				StateFunc:   getMD5Hash,
+				Description: "Artifactory only keeps it encrypted, so only its hash is in state",
+			},
+		},
+	}
+}
+
+// unpackLdapSetting only sends the manager password when it changed. What's in state is its hash, and the patch
+// leaves out what it doesn't name
+func unpackLdapSetting(s *schema.ResourceData) *LdapSetting {
+	d := &ResourceData{s}
+	return &LdapSetting{
+		Key:                      d.getString("key", false),
+		Enabled:                  d.getBool("enabled", false),
+		LdapUrl:                  d.getString("url", false),
+		UserDnPattern:            d.getString("user_dn_pattern", false),
+		EmailAttribute:           d.getString("email_attribute", false),
+		AutoCreateUser:           d.getBool("auto_create_user", false),
+		LdapPoisoningProtection:  d.getBool("ldap_poisoning_protection", false),
+		AllowUserToAccessProfile: d.getBool("allow_user_to_access_profile", false),
+		PagingSupportEnabled:     d.getBool("paging_support_enabled", false),
+		Search: LdapSearch{
+			SearchFilter:    d.getString("search_filter", false),
+			SearchBase:      d.getString("search_base", false),
+			SearchSubTree:   d.getBool("search_sub_tree", false),
+			ManagerDn:       d.getString("manager_dn", false),
+			ManagerPassword: d.getString("manager_password", true),
+		},
+	}
+}
+
+// packLdapSetting leaves manager_password alone, the configuration only has it encrypted
+func packLdapSetting(setting *LdapSetting, d *schema.ResourceData) diag.Diagnostics {
+	setValue := mkLens(d)
+
+	setValue("key", setting.Key)
+	setValue("enabled", setting.Enabled)
+	setValue("url", setting.LdapUrl)
+	setValue("user_dn_pattern", setting.UserDnPattern)
+	setValue("email_attribute", setting.EmailAttribute)
+	setValue("auto_create_user", setting.AutoCreateUser)
+	setValue("ldap_poisoning_protection", setting.LdapPoisoningProtection)
+	setValue("allow_user_to_access_profile", setting.AllowUserToAccessProfile)
+	setValue("paging_support_enabled", setting.PagingSupportEnabled)
+	setValue("search_filter", setting.Search.SearchFilter)
+	setValue("search_base", setting.Search.SearchBase)
+	setValue("search_sub_tree", setting.Search.SearchSubTree)
+	errors := setValue("manager_dn", setting.Search.ManagerDn)
+
+	if errors != nil && len(errors) > 0 {
+		return diag.Errorf("failed to pack ldap setting %q", errors)
+	}
+	return nil
+}
+
+func resourceLdapSettingRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	config := LdapConfiguration{}
+	if err := getConfiguration(ctx, m, &config); err != nil {
+		return diag.Errorf("failed to retrieve the ldap settings during Read: %s", err)
+	}
+	for _, setting := range config.Settings {
+		if setting.Key == d.Id() {
+			return packLdapSetting(&setting, d)
+		}
+	}
+	d.SetId("")
+	return nil
+}
+
+func resourceLdapSettingUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	setting := unpackLdapSetting(d)
+	content, err := yaml.Marshal(&LdapSecurity{LdapSettingsWrapper{map[string]*LdapSetting{setting.Key: setting}}})
+	if err != nil {
+		return diag.Errorf("failed to marshal ldap setting during Update")
+	}
+
+	err = sendConfigurationPatch(ctx, content, m)
+	if err != nil {
+		return diag.Errorf("failed to send PATCH request to Artifactory during Update: %s", err)
+	}
+
+	d.SetId(setting.Key)
+	return resourceLdapSettingRead(ctx, d, m)
+}
+
+func resourceLdapSettingDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	content, err := yaml.Marshal(&LdapSecurity{LdapSettingsWrapper{map[string]*LdapSetting{d.Id(): nil}}})
+	if err != nil {
+		return diag.Errorf("failed to marshal ldap setting during Delete")
+	}
+
+	err = sendConfigurationPatch(ctx, content, m)
+	if err != nil {
+		return diag.Errorf("failed to send PATCH request to Artifactory during Delete: %s", err)
+	}
+	return nil
+}
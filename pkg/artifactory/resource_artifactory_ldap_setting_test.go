@@ -0,0 +1,195 @@
+package artifactory
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccLdapSetting_full(t *testing.T) {
+	_, fqrn, name := mkNames("ldap", "artifactory_ldap_setting")
+	_, groupFqrn, groupName := mkNames("ldap-groups", "artifactory_ldap_group_setting")
+	temp := `
+		resource "artifactory_ldap_setting" "{{ .name }}" {
+			key              = "{{ .name }}"
+			url              = "ldap://ldap.example.com:389/dc=example,dc=com"
+			user_dn_pattern  = "uid={0},ou=People"
+			search_filter    = "(uid={0})"
+			search_base      = "ou=Users"
+			manager_dn       = "cn=manager,dc=example,dc=com"
+			manager_password = "secret"
+			auto_create_user = {{ .autoCreate }}
+		}
+
+		resource "artifactory_ldap_group_setting" "{{ .groupName }}" {
+			name             = "{{ .groupName }}"
+			ldap_setting_key = artifactory_ldap_setting.{{ .name }}.key
+			strategy         = "static"
+			group_base_dn    = "ou=Groups"
+		}
+	`
+	params := map[string]string{"name": name, "groupName": groupName, "autoCreate": "true"}
+	config := executeTemplate(name, temp, params)
+	params["autoCreate"] = "false"
+	updated := executeTemplate(name, temp, params)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() { testAccPreCheck(t) },
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccLdapSettingDestroy(fqrn),
+			testAccLdapSettingDestroy(groupFqrn),
+		),
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fqrn, "url", "ldap://ldap.example.com:389/dc=example,dc=com"),
+					resource.TestCheckResourceAttr(fqrn, "search_filter", "(uid={0})"),
+					resource.TestCheckResourceAttr(fqrn, "auto_create_user", "true"),
+					resource.TestCheckResourceAttr(groupFqrn, "ldap_setting_key", name),
+					resource.TestCheckResourceAttr(groupFqrn, "group_member_attribute", "uniqueMember"),
+				),
+			},
+			{
+				Config: updated,
+				Check:  resource.TestCheckResourceAttr(fqrn, "auto_create_user", "false"),
+			},
+			{
+				ResourceName:            fqrn,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"manager_password"},
+			},
+		},
+	})
+}
+
+// testAccLdapSettingDestroy checks either an ldap setting or an ldap group setting is gone from the configuration
+func testAccLdapSettingDestroy(id string) func(*terraform.State) error {
+	return func(s *terraform.State) error {
+		provider, _ := testAccProviders["artifactory"]()
+
+		rs, ok := s.RootModule().Resources[id]
+		if !ok {
+			return fmt.Errorf("error: resource id [%s] not found", id)
+		}
+		config := LdapConfiguration{}
+		if err := getConfiguration(context.Background(), provider.Meta(), &config); err != nil {
+			return err
+		}
+		for _, setting := range config.Settings {
+			if setting.Key == rs.Primary.ID {
+				return fmt.Errorf("error: ldap setting %s still exists", rs.Primary.ID)
+			}
+		}
+		for _, setting := range config.GroupSettings {
+			if setting.Name == rs.Primary.ID {
+				return fmt.Errorf("error: ldap group setting %s still exists", rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+}
+
+func TestLdapSettingsLeaveOthersAlone(t *testing.T) {
+	meta, _ := newFakeMeta(t)
+	ctx := context.Background()
+	res := resourceArtifactoryLdapSetting()
+
+	create := func(key string) *schema.ResourceData {
+		d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{
+			"key":              key,
+			"url":              "ldap://ldap.example.com:389/dc=example,dc=com",
+			"search_filter":    "(&(objectClass=person)(uid={0}))",
+			"manager_password": "secret",
+		})
+		if diags := res.CreateContext(ctx, d, meta); diags.HasError() {
+			t.Fatalf("create failed: %v", diags)
+		}
+		return d
+	}
+	corp := create("corp")
+	partners := create("partners")
+
+	assert.Equal(t, "(&(objectClass=person)(uid={0}))", corp.Get("search_filter"))
+	assert.Equal(t, "mail", corp.Get("email_attribute"))
+	assert.Equal(t, getMD5Hash("secret"), corp.State().Attributes["manager_password"], "only the hash of the password is kept")
+
+	group := resourceArtifactoryLdapGroupSetting()
+	groups := schema.TestResourceDataRaw(t, group.Schema, map[string]interface{}{
+		"name":             "corp-groups",
+		"ldap_setting_key": "corp",
+		"strategy":         "dynamic",
+		"sub_tree":         false,
+	})
+	if diags := group.CreateContext(ctx, groups, meta); diags.HasError() {
+		t.Fatalf("create failed: %v", diags)
+	}
+	assert.Equal(t, false, groups.Get("sub_tree"))
+	assert.Equal(t, "(objectClass=groupOfNames)", groups.Get("filter"))
+
+	if diags := res.DeleteContext(ctx, partners, meta); diags.HasError() {
+		t.Fatalf("delete failed: %v", diags)
+	}
+	if diags := res.ReadContext(ctx, partners, meta); diags.HasError() || partners.Id() != "" {
+		t.Errorf("expected a deleted setting to clear the id, got %q %v", partners.Id(), diags)
+	}
+	if diags := res.ReadContext(ctx, corp, meta); diags.HasError() || corp.Id() == "" {
+		t.Errorf("expected corp to outlive the delete of another setting, got %v", diags)
+	}
+	if diags := group.ReadContext(ctx, groups, meta); diags.HasError() || groups.Id() == "" {
+		t.Errorf("expected corp-groups to outlive the delete of another setting, got %v", diags)
+	}
+}
+
+func TestLdapSettingManagerPassword(t *testing.T) {
+	meta, _ := newFakeMeta(t)
+	ctx := context.Background()
+	res := resourceArtifactoryLdapSetting()
+	// the fake encrypts passwords as AM.<base64>
+	sentPassword := func() string {
+		config := LdapConfiguration{}
+		if err := getConfiguration(ctx, meta, &config); err != nil {
+			t.Fatal(err)
+		}
+		plain, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(config.Settings[0].Search.ManagerPassword, "AM."))
+		return string(plain)
+	}
+
+	raw := map[string]interface{}{
+		"key":              "corp",
+		"url":              "ldap://ldap.example.com:389/dc=example,dc=com",
+		"manager_dn":       "cn=admin,dc=example,dc=com",
+		"manager_password": "secret",
+	}
+	d := schema.TestResourceDataRaw(t, res.Schema, raw)
+	if diags := res.CreateContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("create failed: %v", diags)
+	}
+	state := d.State()
+	assert.Equal(t, "secret", sentPassword())
+
+	// an update that leaves the password alone mustn't send the hash in its place
+	raw["search_filter"] = "(uid={0})"
+	diff, err := res.Diff(ctx, state, terraform.NewResourceConfigRaw(raw), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.Attributes["manager_password"] != nil {
+		t.Errorf("expected the same password to be no change, got %v", diff.Attributes["manager_password"])
+	}
+	updated, diags := res.Apply(ctx, state, diff, meta)
+	if diags.HasError() {
+		t.Fatalf("update failed: %v", diags)
+	}
+	assert.Equal(t, "(uid={0})", updated.Attributes["search_filter"])
+	assert.Equal(t, "secret", sentPassword())
+}
@@ -12,6 +12,9 @@ var legacyLocalReadFun = mkRepoRead(saveLocalRepoState, func() interface{} {
 })
 
 var legacyLocalSchema = map[string]*schema.Schema{
+	"project_key":              repoProjectKeySchema,
+	"skip_default_project_key": skipDefaultProjectKeySchema,
+	"project_environments":     repoProjectEnvironmentsSchema,
 	"key": {
 		Type:         schema.TypeString,
 		Required:     true,
@@ -134,13 +137,14 @@ var legacyLocalSchema = map[string]*schema.Schema{
 
 func resourceArtifactoryLocalRepository() *schema.Resource {
 	return &schema.Resource{
-		Create: mkRepoCreate(unmarshalLocalRepository, legacyLocalReadFun),
-		Read:   legacyLocalReadFun,
-		Update: mkRepoUpdate(unmarshalLocalRepository, legacyLocalReadFun),
-		Delete: deleteRepo,
-		Exists: repoExists,
+		CreateContext: mkRepoCreate(unmarshalLocalRepository, legacyLocalReadFun),
+		ReadContext:   legacyLocalReadFun,
+		UpdateContext: mkRepoUpdate(unmarshalLocalRepository, legacyLocalReadFun),
+		DeleteContext: deleteRepo,
+		Timeouts:      defaultResourceTimeouts(),
+		CustomizeDiff: defaultProjectKey,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: legacyLocalSchema,
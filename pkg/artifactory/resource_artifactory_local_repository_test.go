@@ -1,12 +1,16 @@
 package artifactory
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestAccLocalAlpineRepository(t *testing.T) {
@@ -406,3 +410,106 @@ func TestAccAllRepoTypesLocal(t *testing.T) {
 		})
 	}
 }
+
+// TestLocalRepositoryFixture replays a create/update/delete captured from the fake, checking both what unpack sends
+// and what pack makes of the answers
+func TestLocalRepositoryFixture(t *testing.T) {
+	meta := fixtureMeta(t, "local_repository")
+	ctx := context.Background()
+
+	res := resourceArtifactoryLocalRepository()
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{
+		"key":              "fixture-local",
+		"package_type":     "npm",
+		"description":      "before",
+		"includes_pattern": "**/*",
+	})
+	if diags := res.CreateContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("create failed: %v", diags)
+	}
+	if d.Id() != "fixture-local" || d.Get("package_type") != "npm" || d.Get("description") != "before" {
+		t.Errorf("unexpected state after create: %s %v %v", d.Id(), d.Get("package_type"), d.Get("description"))
+	}
+
+	if err := d.Set("description", "after"); err != nil {
+		t.Fatal(err)
+	}
+	if diags := res.UpdateContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("update failed: %v", diags)
+	}
+	if d.Get("description") != "after" || d.Get("includes_pattern") != "**/*" {
+		t.Errorf("unexpected state after update: %v %v", d.Get("description"), d.Get("includes_pattern"))
+	}
+
+	if diags := res.DeleteContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("delete failed: %v", diags)
+	}
+	if diags := res.ReadContext(ctx, d, meta); diags.HasError() || d.Id() != "" {
+		t.Errorf("expected the repository to be gone after delete, got %v %s", diags, d.Id())
+	}
+}
+
+func TestLocalRepositoryProjectKey(t *testing.T) {
+	meta, _ := newFakeMeta(t)
+	ctx := context.Background()
+	res := resourceArtifactoryLocalRepository()
+	apply := func(state *terraform.InstanceState, raw map[string]interface{}) *terraform.InstanceState {
+		diff, err := res.Diff(ctx, state, terraform.NewResourceConfigRaw(raw), meta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff == nil {
+			return state
+		}
+		applied, diags := res.Apply(ctx, state, diff, meta)
+		if diags.HasError() {
+			t.Fatalf("apply failed: %v", diags)
+		}
+		return applied
+	}
+
+	// a repository from before the provider had a project_key stays where it is
+	existingConfig := map[string]interface{}{"key": "existing", "package_type": "generic"}
+	existing := apply(nil, existingConfig)
+	meta.ProjectKey = "default"
+	existingConfig["description"] = "updated"
+	existing = apply(existing, existingConfig)
+	assert.Equal(t, "", existing.Attributes["project_key"], "setting the provider's project_key mustn't move existing repositories")
+	assert.Equal(t, "updated", existing.Attributes["description"])
+
+	inheritedConfig := map[string]interface{}{"key": "inherits-project", "package_type": "generic"}
+	plan, err := res.Diff(ctx, nil, terraform.NewResourceConfigRaw(inheritedConfig), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "default", plan.Attributes["project_key"].New, "the provider's project key is in the plan")
+	inherited := apply(nil, inheritedConfig)
+	assert.Equal(t, "default", inherited.Attributes["project_key"])
+
+	skipped := apply(nil, map[string]interface{}{"key": "no-project", "package_type": "generic", "skip_default_project_key": true})
+	assert.Equal(t, "", skipped.Attributes["project_key"])
+
+	overridden := apply(nil, map[string]interface{}{
+		"key":                  "own-project",
+		"package_type":         "generic",
+		"project_key":          "other",
+		"project_environments": []interface{}{"PROD"},
+	})
+	assert.Equal(t, "other", overridden.Attributes["project_key"])
+	assert.Equal(t, "1", overridden.Attributes["project_environments.#"], "expected the project environments to round trip")
+}
+
+func TestProjectKeyRequiresVersion(t *testing.T) {
+	meta := &ProviderMetadata{ArtifactoryVersion: "7.10.2"}
+	ctx := context.Background()
+	res := resourceArtifactoryLocalRepository()
+
+	raw := map[string]interface{}{"key": "old-local", "package_type": "generic", "project_key": "myproj"}
+	if _, err := res.Diff(ctx, nil, terraform.NewResourceConfigRaw(raw), meta); err == nil {
+		t.Error("expected a project_key to need a newer artifactory")
+	}
+	delete(raw, "project_key")
+	if _, err := res.Diff(ctx, nil, terraform.NewResourceConfigRaw(raw), meta); err != nil {
+		t.Errorf("expected a repository without a project to plan, got %s", err)
+	}
+}
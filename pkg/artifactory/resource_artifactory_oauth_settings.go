@@ -2,8 +2,8 @@ package artifactory
 
 import (
 	"context"
+	"fmt"
 
-	"github.com/go-resty/resty/v2"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -32,7 +32,7 @@ type OauthProviderSettings struct {
 	Enabled      bool   `yaml:"enabled" json:"enabled"`
 	Type         string `yaml:"providerType" json:"providerType"`
 	ClientId     string `yaml:"id" json:"id"`
-	ClientSecret string `yaml:"secret" json:"secret"`
+	ClientSecret string `yaml:"secret,omitempty" json:"secret"`
 	ApiUrl       string `yaml:"apiUrl" json:"apiUrl"`
 	AuthUrl      string `yaml:"authUrl" json:"authUrl"`
 	TokenUrl     string `yaml:"tokenUrl" json:"tokenUrl"`
@@ -47,14 +47,15 @@ type OauthType struct {
 }
 
 func resourceArtifactoryOauthSettings() *schema.Resource {
-	return &schema.Resource{
+	return upgradeFromVersion0(&schema.Resource{
 		UpdateContext: resourceOauthSettingsUpdate,
 		CreateContext: resourceOauthSettingsUpdate,
 		DeleteContext: resourceOauthSettingsDelete,
 		ReadContext:   resourceOauthSettingsRead,
+		Timeouts:      defaultResourceTimeouts(),
 
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -77,6 +78,7 @@ func resourceArtifactoryOauthSettings() *schema.Resource {
 				Type:     schema.TypeSet,
 				Required: true,
 				MinItems: 1,
+				Set:      hashOauthProvider,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"name": {
@@ -97,9 +99,11 @@ func resourceArtifactoryOauthSettings() *schema.Resource {
 							Required: true,
 						},
 						"client_secret": {
-							Type:      schema.TypeString,
-							Required:  true,
-							Sensitive: true,
+							Type:        schema.TypeString,
+							Required:    true,
+							Sensitive:   true,
+							StateFunc:   getMD5Hash,
+							Description: "Only its salted hash is kept in state",
 						},
 						"api_url": {
 							Type:     schema.TypeString,
@@ -117,17 +121,43 @@ func resourceArtifactoryOauthSettings() *schema.Resource {
 				},
 			},
 		},
+	}, upgradeOauthSettingsState)
+}
+
+// upgradeOauthSettingsState hashes the client secrets written to state before they were hashed. Artifactory only hands
+// back encrypted ones, which would otherwise keep the plain text secret in state for good
+func upgradeOauthSettingsState(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	if providers, ok := rawState["oauth_provider"].([]interface{}); ok {
+		for _, provider := range providers {
+			if p, ok := provider.(map[string]interface{}); ok {
+				if secret, ok := p["client_secret"].(string); ok && !isMD5Hash(secret) {
+					p["client_secret"] = getMD5Hash(secret)
+				}
+			}
+		}
+	}
+	return rawState, nil
+}
+
+// hashOauthProvider hashes the hash of client_secret, which is the secret in config but already its hash in state
+func hashOauthProvider(v interface{}) int {
+	p := v.(map[string]interface{})
+	clientSecret := p["client_secret"].(string)
+	if !isMD5Hash(clientSecret) {
+		clientSecret = getMD5Hash(clientSecret)
 	}
+	return schema.HashString(fmt.Sprintf("%s-%t-%s-%s-%s-%s-%s-%s",
+		p["name"], p["enabled"], p["type"], p["client_id"], clientSecret, p["api_url"], p["auth_url"], p["token_url"]))
 }
 
 func resourceOauthSettingsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*resty.Client)
+	c := m.(*ProviderMetadata).Client
 
 	oauthSettings := OauthSettings{}
 
-	_, err := c.R().SetResult(&oauthSettings).Get("artifactory/api/oauth")
+	_, err := c.R().SetContext(ctx).SetResult(&oauthSettings).Get("artifactory/api/oauth")
 	if err != nil {
-		return diag.Errorf("failed to retrieve data from <base_url>/artifactory/api/oauth during Read")
+		return diag.Errorf("failed to retrieve data from <base_url>/artifactory/api/oauth during Read: %s", err)
 	}
 
 	s := OauthSecurity{OauthSettingsWrapper{Settings: oauthSettings}}
@@ -162,9 +192,9 @@ func resourceOauthSettingsUpdate(ctx context.Context, d *schema.ResourceData, m
 		return diag.Errorf("failed to marshal oauth settings during Update")
 	}
 
-	err = sendConfigurationPatch(content, m)
+	err = sendConfigurationPatch(ctx, content, m)
 	if err != nil {
-		return diag.Errorf("failed to send PATCH request to Artifactory during Update")
+		return diag.Errorf("failed to send PATCH request to Artifactory during Update: %s", err)
 	}
 
 	// we should only have one oauth settings resource, using same id
@@ -172,15 +202,15 @@ func resourceOauthSettingsUpdate(ctx context.Context, d *schema.ResourceData, m
 	return resourceOauthSettingsRead(ctx, d, m)
 }
 
-func resourceOauthSettingsDelete(_ context.Context, _ *schema.ResourceData, m interface{}) diag.Diagnostics {
+func resourceOauthSettingsDelete(ctx context.Context, _ *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var content = `
 security:
   oauthSettings: ~
 `
 
-	err := sendConfigurationPatch([]byte(content), m)
+	err := sendConfigurationPatch(ctx, []byte(content), m)
 	if err != nil {
-		return diag.Errorf("failed to send PATCH request to Artifactory during Delete")
+		return diag.Errorf("failed to send PATCH request to Artifactory during Delete: %s", err)
 	}
 	return nil
 }
@@ -201,12 +231,18 @@ func unpackOauthSecurity(s *schema.ResourceData) *OauthSecurity {
 		for _, m := range v.(*schema.Set).List() {
 			o := m.(map[string]interface{})
 
+			// the hash in state is an unchanged secret, which the patch leaves as it is
+			clientSecret := o["client_secret"].(string)
+			if isMD5Hash(clientSecret) {
+				clientSecret = ""
+			}
+
 			oauthProviderSettings[o["name"].(string)] = OauthProviderSettings{
 				Name:         o["name"].(string),
 				Enabled:      o["enabled"].(bool),
 				Type:         o["type"].(string),
 				ClientId:     o["client_id"].(string),
-				ClientSecret: o["client_secret"].(string),
+				ClientSecret: clientSecret,
 				ApiUrl:       o["api_url"].(string),
 				AuthUrl:      o["auth_url"].(string),
 				TokenUrl:     o["token_url"].(string),
@@ -219,22 +255,36 @@ func unpackOauthSecurity(s *schema.ResourceData) *OauthSecurity {
 	return security
 }
 
+// packOauthSecurity keeps the client secret in state when artifactory only has it encrypted. Otherwise its
+// fingerprint replaces it, and a secret changed in the UI shows up as a diff
 func packOauthSecurity(s *OauthSecurity, d *schema.ResourceData) diag.Diagnostics {
 	setValue := mkLens(d)
 
 	setValue("enable", s.Oauth.Settings.EnableIntegration)
 	setValue("persist_users", s.Oauth.Settings.PersistUsers)
-	errors := setValue("allow_user_to_access_profile", s.Oauth.Settings.AllowUserToAccessProfile)
+	setValue("allow_user_to_access_profile", s.Oauth.Settings.AllowUserToAccessProfile)
+
+	secrets := map[string]string{}
+	if v, ok := d.GetOk("oauth_provider"); ok {
+		for _, provider := range v.(*schema.Set).List() {
+			p := provider.(map[string]interface{})
+			secrets[p["name"].(string)] = p["client_secret"].(string)
+		}
+	}
 
 	settings := make([]interface{}, 0)
 
 	for name, setting := range s.Oauth.Settings.OauthProvidersSettings {
+		clientSecret, ok := secretFingerprint(setting.ClientSecret)
+		if !ok {
+			clientSecret = secrets[name]
+		}
 		providerSetting := map[string]interface{}{
 			"name":          name,
 			"enabled":       setting.Enabled,
 			"type":          setting.Type,
 			"client_id":     setting.ClientId,
-			"client_secret": setting.ClientSecret,
+			"client_secret": clientSecret,
 			"api_url":       setting.ApiUrl,
 			"auth_url":      setting.AuthUrl,
 			"token_url":     setting.TokenUrl,
@@ -243,6 +293,8 @@ func packOauthSecurity(s *OauthSecurity, d *schema.ResourceData) diag.Diagnostic
 		settings = append(settings, providerSetting)
 	}
 
+	errors := setValue("oauth_provider", schema.NewSet(hashOauthProvider, settings))
+
 	if errors != nil && len(errors) > 0 {
 		return diag.Errorf("failed to pack oauth settings %q", errors)
 	}
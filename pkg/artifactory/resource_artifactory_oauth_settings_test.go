@@ -1,13 +1,13 @@
 package artifactory
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
-	"github.com/go-resty/resty/v2"
-
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
 )
 
 const OauthSettingsTemplateFull = `
@@ -99,7 +99,7 @@ func TestAccOauthSettings_multipleProviders(t *testing.T) {
 func testAccOauthSettingsDestroy(id string) func(*terraform.State) error {
 	return func(s *terraform.State) error {
 		provider, _ := testAccProviders["artifactory"]()
-		client := provider.Meta().(*resty.Client)
+		client := provider.Meta().(*ProviderMetadata).Client
 
 		_, ok := s.RootModule().Resources[id]
 		if !ok {
@@ -127,3 +127,98 @@ func testAccOauthSettingsDestroy(id string) func(*terraform.State) error {
 		return nil
 	}
 }
+
+func TestOauthClientSecretDrift(t *testing.T) {
+	meta, _ := newFakeMeta(t)
+	ctx := context.Background()
+	res := resourceArtifactoryOauthSettings()
+
+	provider := map[string]interface{}{
+		"name":          "okta",
+		"type":          "openId",
+		"client_id":     "foo",
+		"client_secret": "bar",
+		"api_url":       "https://organization.okta.com/oauth2/v1/userinfo",
+		"auth_url":      "https://organization.okta.com/oauth2/v1/authorize",
+		"token_url":     "https://organization.okta.com/oauth2/v1/token",
+	}
+	raw := map[string]interface{}{"oauth_provider": []interface{}{provider}}
+	plan := func(state *terraform.InstanceState) *terraform.InstanceDiff {
+		diff, err := res.Diff(ctx, state, terraform.NewResourceConfigRaw(raw), meta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return diff
+	}
+	read := func(state *terraform.InstanceState) *terraform.InstanceState {
+		refreshed, diags := res.RefreshWithoutUpgrade(ctx, state, meta)
+		if diags.HasError() {
+			t.Fatalf("read failed: %v", diags)
+		}
+		return refreshed
+	}
+	secret := func(state *terraform.InstanceState) string {
+		for key, value := range state.Attributes {
+			if len(key) > len("client_secret") && key[len(key)-len("client_secret"):] == "client_secret" {
+				return value
+			}
+		}
+		return ""
+	}
+
+	state, diags := res.Apply(ctx, nil, plan(nil), meta)
+	if diags.HasError() {
+		t.Fatalf("create failed: %v", diags)
+	}
+	assert.Equal(t, getMD5Hash("bar"), secret(state), "only the hash of the secret is kept")
+	if diff := plan(read(state)); diff != nil && !diff.Empty() {
+		t.Errorf("expected no diff for the same secret, got %v", diff)
+	}
+
+	// an update that leaves the secret alone keeps it
+	raw["persist_users"] = true
+	state, diags = res.Apply(ctx, state, plan(state), meta)
+	if diags.HasError() {
+		t.Fatalf("update failed: %v", diags)
+	}
+	assert.Equal(t, getMD5Hash("bar"), secret(read(state)), "the hash in state mustn't be sent as the secret")
+
+	// changed in the UI
+	patch := "security:\n  oauthSettings:\n    oauthProvidersSettings:\n      okta:\n        secret: baz\n"
+	if err := sendConfigurationPatch(ctx, []byte(patch), meta); err != nil {
+		t.Fatal(err)
+	}
+	state = read(state)
+	diff := plan(state)
+	if diff == nil || diff.Empty() {
+		t.Fatal("expected a secret changed in the UI to show up as a diff")
+	}
+	state, diags = res.Apply(ctx, state, diff, meta)
+	if diags.HasError() {
+		t.Fatalf("update failed: %v", diags)
+	}
+	assert.Equal(t, getMD5Hash("bar"), secret(read(state)), "the apply puts the configured secret back")
+}
+
+func TestUpgradeOauthSettingsState(t *testing.T) {
+	state := upgradeState(t, resourceArtifactoryOauthSettings(), `{
+		"id": "oauth_settings",
+		"enable": true,
+		"persist_users": false,
+		"allow_user_to_access_profile": false,
+		"oauth_provider": [{
+			"name": "github",
+			"enabled": true,
+			"type": "github",
+			"client_id": "id",
+			"client_secret": "hunter2",
+			"api_url": "https://api.github.com/user",
+			"auth_url": "https://github.com/login/oauth/authorize",
+			"token_url": "https://github.com/login/oauth/access_token"
+		}]
+	}`)
+	provider := state["oauth_provider"].([]interface{})[0].(map[string]interface{})
+	if provider["client_secret"] != getMD5Hash("hunter2") {
+		t.Errorf("expected the plain text client secret to be hashed, got %v", provider["client_secret"])
+	}
+}
@@ -1,13 +1,13 @@
 package artifactory
 
 import (
+	"context"
 	"fmt"
-	"net/http"
 	"strings"
 
-	"github.com/go-resty/resty/v2"
 	"github.com/jfrog/jfrog-client-go/artifactory/services"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -107,14 +107,14 @@ func resourceArtifactoryPermissionTarget() *schema.Resource {
 	buildSchema.Elem.(*schema.Resource).Schema["repositories"].Description = `This can only be 1 value: "artifactory-build-info", and currently, validation of sets/lists is not allowed. Artifactory will reject the request if you change this`
 
 	return &schema.Resource{
-		Create: resourcePermissionTargetCreate,
-		Read:   resourcePermissionTargetRead,
-		Update: resourcePermissionTargetUpdate,
-		Delete: resourcePermissionTargetDelete,
-		Exists: resourcePermissionTargetExists,
+		CreateContext: resourcePermissionTargetCreate,
+		ReadContext:   resourcePermissionTargetRead,
+		UpdateContext: resourcePermissionTargetUpdate,
+		DeleteContext: resourcePermissionTargetDelete,
+		Timeouts:      defaultResourceTimeouts(),
 
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -292,65 +292,62 @@ func packPermissionTarget(permissionTarget *services.PermissionTargetParams, d *
 	return nil
 }
 
-func resourcePermissionTargetCreate(d *schema.ResourceData, m interface{}) error {
+func resourcePermissionTargetCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	permissionTarget := unpackPermissionTarget(d)
 
-	if _, err := m.(*resty.Client).R().SetBody(permissionTarget).Post(permissionsEndPoint + permissionTarget.Name); err != nil {
-		return err
+	if _, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).SetBody(permissionTarget).Post(permissionsEndPoint + permissionTarget.Name); err != nil {
+		return diag.FromErr(err)
 	}
 
 	d.SetId(permissionTarget.Name)
-	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-		exists, err := resourcePermissionTargetExists(d, m)
+	return diag.FromErr(resource.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		_, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).Head(permissionsEndPoint + d.Id())
+		if IsNotFound(err) {
+			return resource.RetryableError(fmt.Errorf("expected permission target to be created, but currently not found"))
+		}
 		if err != nil {
 			return resource.NonRetryableError(fmt.Errorf("error describing permssions target: %s", err))
 		}
 
-		if !exists {
-			return resource.RetryableError(fmt.Errorf("expected permission target to be created, but currently not found"))
-		}
-
 		return nil
-	})
+	}))
 }
 
-func resourcePermissionTargetRead(d *schema.ResourceData, m interface{}) error {
+func resourcePermissionTargetRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	permissionTarget := new(services.PermissionTargetParams)
-	resp, err := m.(*resty.Client).R().SetResult(permissionTarget).Get(permissionsEndPoint + d.Id())
+	_, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).SetResult(permissionTarget).Get(permissionsEndPoint + d.Id())
 	if err != nil {
-		if resp != nil && resp.StatusCode() == http.StatusNotFound {
+		if IsNotFound(err) {
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diag.FromErr(err)
 	}
 
-	return packPermissionTarget(permissionTarget, d)
+	return diag.FromErr(packPermissionTarget(permissionTarget, d))
 }
 
-func resourcePermissionTargetUpdate(d *schema.ResourceData, m interface{}) error {
+func resourcePermissionTargetUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	permissionTarget := unpackPermissionTarget(d)
 
-	if _, err := m.(*resty.Client).R().SetBody(permissionTarget).Put(permissionsEndPoint + d.Id()); err != nil {
-		return err
+	if _, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).SetBody(permissionTarget).Put(permissionsEndPoint + d.Id()); err != nil {
+		return diag.FromErr(err)
 	}
 
 	d.SetId(permissionTarget.Name)
-	return resourcePermissionTargetRead(d, m)
+	return resourcePermissionTargetRead(ctx, d, m)
 }
 
-func resourcePermissionTargetDelete(d *schema.ResourceData, m interface{}) error {
-	_, err := m.(*resty.Client).R().Delete(permissionsEndPoint + d.Id())
-
-	return err
-}
-
-func resourcePermissionTargetExists(d *schema.ResourceData, m interface{}) (bool, error) {
-	return permTargetExists(d.Id(), m)
+func resourcePermissionTargetDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	_, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).Delete(permissionsEndPoint + d.Id())
+	if IsNotFound(err) {
+		return nil
+	}
+	return diag.FromErr(err)
 }
 
 func permTargetExists(id string, m interface{}) (bool, error) {
-	_, err := m.(*resty.Client).R().Head(permissionsEndPoint + id)
+	_, err := m.(*ProviderMetadata).Client.R().Head(permissionsEndPoint + id)
 
 	return err == nil, err
 }
@@ -0,0 +1,465 @@
+package artifactory
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const projectsEndpoint = "access/api/v1/projects"
+
+// projectEndpoint is the path of a project, or of something in it, e.g. projectEndpoint(key, "roles", name)
+func projectEndpoint(key string, path ...string) string {
+	return strings.Join(append([]string{projectsEndpoint, key}, path...), "/")
+}
+
+// projectsMinVersion is the first artifactory with projects. Older ones answer the api with a bare 404
+const projectsMinVersion = "7.17.0"
+
+const gibibyte = 1024 * 1024 * 1024
+
+type AdminPrivileges struct {
+	ManageMembers   bool `json:"manage_members"`
+	ManageResources bool `json:"manage_resources"`
+	IndexResources  bool `json:"index_resources"`
+}
+
+type Project struct {
+	Key               string          `json:"project_key"`
+	DisplayName       string          `json:"display_name"`
+	Description       string          `json:"description"`
+	AdminPrivileges   AdminPrivileges `json:"admin_privileges"`
+	StorageQuotaBytes int64           `json:"storage_quota_bytes"`
+}
+
+type ProjectMember struct {
+	Name  string   `json:"name"`
+	Roles []string `json:"roles"`
+}
+
+type ProjectMembers struct {
+	Members []ProjectMember `json:"members"`
+}
+
+type ProjectRole struct {
+	Name         string   `json:"name"`
+	Description  string   `json:"description,omitempty"`
+	Type         string   `json:"type"`
+	Environments []string `json:"environments"`
+	Actions      []string `json:"actions"`
+}
+
+// customRoleType is the only type of role the resource manages. The predefined ones (Developer, Viewer...) come
+// with every project
+const customRoleType = "CUSTOM"
+
+func resourceArtifactoryProject() *schema.Resource {
+	memberSchema := &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.StringIsNotEmpty,
+				},
+				"roles": {
+					Type:     schema.TypeSet,
+					Required: true,
+					MinItems: 1,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+					Set:      schema.HashString,
+				},
+			},
+		},
+	}
+
+	return &schema.Resource{
+		CreateContext: resourceProjectCreate,
+		ReadContext:   resourceProjectRead,
+		UpdateContext: resourceProjectUpdate,
+		DeleteContext: resourceProjectDelete,
+		Timeouts:      defaultResourceTimeouts(),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"key": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateProjectKey,
+			},
+			"display_name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringLenBetween(1, 32),
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"max_storage_in_gibibytes": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  -1,
+				// artifactory takes 0 as no quota, which would read back as -1
+				ValidateFunc: validation.Any(validation.IntInSlice([]int{-1}), validation.IntAtLeast(1)),
+				Description:  "Storage quota of the project. -1 means no quota",
+			},
+			"admin_privileges": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"manage_members": {
+							Type:     schema.TypeBool,
+							Required: true,
+						},
+						"manage_resources": {
+							Type:     schema.TypeBool,
+							Required: true,
+						},
+						"index_resources": {
+							Type:     schema.TypeBool,
+							Required: true,
+						},
+					},
+				},
+			},
+			"member": memberSchema,
+			"group":  memberSchema,
+			"role": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringIsNotEmpty,
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"environments": {
+							Type:     schema.TypeSet,
+							Required: true,
+							MinItems: 1,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validation.StringInSlice([]string{"DEV", "PROD"}, false),
+							},
+							Set: schema.HashString,
+						},
+						"actions": {
+							Type:     schema.TypeSet,
+							Required: true,
+							MinItems: 1,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Set:      schema.HashString,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func unpackProject(s *schema.ResourceData) Project {
+	d := &ResourceData{s}
+	project := Project{
+		Key:               d.getString("key", false),
+		DisplayName:       d.getString("display_name", false),
+		Description:       d.getString("description", false),
+		StorageQuotaBytes: -1,
+	}
+	if quota := d.getInt("max_storage_in_gibibytes", false); quota > 0 {
+		project.StorageQuotaBytes = int64(quota) * gibibyte
+	}
+	if v, ok := d.GetOk("admin_privileges"); ok {
+		privileges := v.([]interface{})[0].(map[string]interface{})
+		project.AdminPrivileges = AdminPrivileges{
+			ManageMembers:   privileges["manage_members"].(bool),
+			ManageResources: privileges["manage_resources"].(bool),
+			IndexResources:  privileges["index_resources"].(bool),
+		}
+	}
+	return project
+}
+
+func unpackProjectMembers(set *schema.Set) []ProjectMember {
+	var members []ProjectMember
+	for _, item := range set.List() {
+		member := item.(map[string]interface{})
+		members = append(members, ProjectMember{
+			Name:  member["name"].(string),
+			Roles: castToStringArr(member["roles"].(*schema.Set).List()),
+		})
+	}
+	return members
+}
+
+func unpackProjectRoles(set *schema.Set) []ProjectRole {
+	var roles []ProjectRole
+	for _, item := range set.List() {
+		role := item.(map[string]interface{})
+		roles = append(roles, ProjectRole{
+			Name:         role["name"].(string),
+			Description:  role["description"].(string),
+			Type:         customRoleType,
+			Environments: castToStringArr(role["environments"].(*schema.Set).List()),
+			Actions:      castToStringArr(role["actions"].(*schema.Set).List()),
+		})
+	}
+	return roles
+}
+
+func packProjectMembers(members []ProjectMember) []interface{} {
+	var result []interface{}
+	for _, member := range members {
+		result = append(result, map[string]interface{}{
+			"name":  member.Name,
+			"roles": schema.NewSet(schema.HashString, castToInterfaceArr(member.Roles)),
+		})
+	}
+	return result
+}
+
+func packProjectRoles(roles []ProjectRole) []interface{} {
+	var result []interface{}
+	for _, role := range roles {
+		if role.Type != customRoleType {
+			continue
+		}
+		result = append(result, map[string]interface{}{
+			"name":         role.Name,
+			"description":  role.Description,
+			"environments": schema.NewSet(schema.HashString, castToInterfaceArr(role.Environments)),
+			"actions":      schema.NewSet(schema.HashString, castToInterfaceArr(role.Actions)),
+		})
+	}
+	return result
+}
+
+// requireProjects fails every call of the resource, import and refresh included, before artifactory answers with a
+// bare 404 or 401. Projects are only licensed with Enterprise X and Enterprise+
+func requireProjects(m interface{}, feature string) error {
+	meta := m.(*ProviderMetadata)
+	if err := meta.requireAccessToken(feature); err != nil {
+		return err
+	}
+	if err := meta.requireEnterprise(feature); err != nil {
+		return err
+	}
+	return meta.requireVersion(feature, projectsMinVersion)
+}
+
+func resourceProjectCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := requireProjects(m, "artifactory_project"); err != nil {
+		return diag.FromErr(err)
+	}
+	project := unpackProject(d)
+	client := m.(*ProviderMetadata).Client
+	if _, err := client.R().SetContext(ctx).SetBody(project).Post(projectsEndpoint); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(project.Key)
+
+	// roles go first, members may be given the custom ones
+	if err := updateProjectRoles(ctx, client, project.Key, nil, unpackProjectRoles(d.Get("role").(*schema.Set))); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := updateProjectMembers(ctx, client, project.Key, "users", nil, unpackProjectMembers(d.Get("member").(*schema.Set))); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := updateProjectMembers(ctx, client, project.Key, "groups", nil, unpackProjectMembers(d.Get("group").(*schema.Set))); err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceProjectRead(ctx, d, m)
+}
+
+func resourceProjectRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := requireProjects(m, "artifactory_project"); err != nil {
+		return diag.FromErr(err)
+	}
+	client := m.(*ProviderMetadata).Client
+	project := Project{}
+	if _, err := client.R().SetContext(ctx).SetResult(&project).Get(projectEndpoint(d.Id())); err != nil {
+		if IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	users := ProjectMembers{}
+	if _, err := client.R().SetContext(ctx).SetResult(&users).Get(projectEndpoint(d.Id(), "users")); err != nil {
+		return diag.FromErr(err)
+	}
+	groups := ProjectMembers{}
+	if _, err := client.R().SetContext(ctx).SetResult(&groups).Get(projectEndpoint(d.Id(), "groups")); err != nil {
+		return diag.FromErr(err)
+	}
+	var roles []ProjectRole
+	if _, err := client.R().SetContext(ctx).SetResult(&roles).Get(projectEndpoint(d.Id(), "roles")); err != nil {
+		return diag.FromErr(err)
+	}
+
+	quota := -1
+	if project.StorageQuotaBytes > 0 {
+		quota = int(project.StorageQuotaBytes / gibibyte)
+	}
+
+	setValue := mkLens(d)
+	setValue("key", project.Key)
+	setValue("display_name", project.DisplayName)
+	setValue("description", project.Description)
+	setValue("max_storage_in_gibibytes", quota)
+	setValue("admin_privileges", []interface{}{
+		map[string]interface{}{
+			"manage_members":   project.AdminPrivileges.ManageMembers,
+			"manage_resources": project.AdminPrivileges.ManageResources,
+			"index_resources":  project.AdminPrivileges.IndexResources,
+		},
+	})
+	setValue("member", packProjectMembers(users.Members))
+	setValue("group", packProjectMembers(groups.Members))
+	errors := setValue("role", packProjectRoles(roles))
+	if errors != nil && len(errors) > 0 {
+		return diag.Errorf("failed saving state for project %q", errors)
+	}
+	return nil
+}
+
+func resourceProjectUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := requireProjects(m, "artifactory_project"); err != nil {
+		return diag.FromErr(err)
+	}
+	client := m.(*ProviderMetadata).Client
+	if d.HasChanges("display_name", "description", "max_storage_in_gibibytes", "admin_privileges") {
+		if _, err := client.R().SetContext(ctx).SetBody(unpackProject(d)).Put(projectEndpoint(d.Id())); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	oldRoles, newRoles := d.GetChange("role")
+	// new and changed roles have to exist before members get them, removed ones are only dropped once nobody has them
+	if err := updateProjectRoles(ctx, client, d.Id(), unpackProjectRoles(oldRoles.(*schema.Set)), unpackProjectRoles(newRoles.(*schema.Set))); err != nil {
+		return diag.FromErr(err)
+	}
+	for kind, key := range map[string]string{"users": "member", "groups": "group"} {
+		if !d.HasChange(key) {
+			continue
+		}
+		oldMembers, newMembers := d.GetChange(key)
+		if err := updateProjectMembers(ctx, client, d.Id(), kind, unpackProjectMembers(oldMembers.(*schema.Set)), unpackProjectMembers(newMembers.(*schema.Set))); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if err := deleteProjectRoles(ctx, client, d.Id(), unpackProjectRoles(oldRoles.(*schema.Set)), unpackProjectRoles(newRoles.(*schema.Set))); err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceProjectRead(ctx, d, m)
+}
+
+func resourceProjectDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := requireProjects(m, "artifactory_project"); err != nil {
+		return diag.FromErr(err)
+	}
+	_, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).Delete(projectEndpoint(d.Id()))
+	if IsNotFound(err) {
+		return nil
+	}
+	return diag.FromErr(err)
+}
+
+// updateProjectMembers puts every member that's new or whose roles changed, and removes the ones that are gone.
+// kind is users or groups
+func updateProjectMembers(ctx context.Context, client *resty.Client, projectKey, kind string, old, new []ProjectMember) error {
+	previous := map[string]ProjectMember{}
+	for _, member := range old {
+		previous[member.Name] = member
+	}
+	for _, member := range new {
+		if was, ok := previous[member.Name]; ok && sameStrings(was.Roles, member.Roles) {
+			delete(previous, member.Name)
+			continue
+		}
+		delete(previous, member.Name)
+		if _, err := client.R().SetContext(ctx).SetBody(member).Put(projectEndpoint(projectKey, kind, member.Name)); err != nil {
+			return err
+		}
+	}
+	for name := range previous {
+		if _, err := client.R().SetContext(ctx).Delete(projectEndpoint(projectKey, kind, name)); err != nil && !IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateProjectRoles creates the roles that are new and updates the ones that changed. Removing is left to
+// deleteProjectRoles
+func updateProjectRoles(ctx context.Context, client *resty.Client, projectKey string, old, new []ProjectRole) error {
+	previous := map[string]ProjectRole{}
+	for _, role := range old {
+		previous[role.Name] = role
+	}
+	for _, role := range new {
+		was, ok := previous[role.Name]
+		switch {
+		case !ok:
+			if _, err := client.R().SetContext(ctx).SetBody(role).Post(projectEndpoint(projectKey, "roles")); err != nil {
+				return err
+			}
+		case was.Description != role.Description || !sameStrings(was.Environments, role.Environments) || !sameStrings(was.Actions, role.Actions):
+			if _, err := client.R().SetContext(ctx).SetBody(role).Put(projectEndpoint(projectKey, "roles", role.Name)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func deleteProjectRoles(ctx context.Context, client *resty.Client, projectKey string, old, new []ProjectRole) error {
+	kept := map[string]bool{}
+	for _, role := range new {
+		kept[role.Name] = true
+	}
+	for _, role := range old {
+		if kept[role.Name] {
+			continue
+		}
+		if _, err := client.R().SetContext(ctx).Delete(projectEndpoint(projectKey, "roles", role.Name)); err != nil && !IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// sameStrings compares two lists as sets
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := map[string]bool{}
+	for _, s := range a {
+		seen[s] = true
+	}
+	for _, s := range b {
+		if !seen[s] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+package artifactory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const projectRepositoryEndpoint = projectsEndpoint + "/_/attach/repositories/"
+
+// resourceArtifactoryProjectRepository assigns a repository that is managed elsewhere to a project. It's the same
+// projectKey the repository resources' project_key sets, so only one of the two should manage a given repository
+func resourceArtifactoryProjectRepository() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceProjectRepositoryAssign,
+		ReadContext:   resourceProjectRepositoryRead,
+		UpdateContext: resourceProjectRepositoryAssign,
+		DeleteContext: resourceProjectRepositoryDelete,
+		Timeouts:      defaultResourceTimeouts(),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"repo_key": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"project_key": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateProjectKey,
+			},
+		},
+	}
+}
+
+func resourceProjectRepositoryAssign(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := requireProjects(m, "artifactory_project_repository"); err != nil {
+		return diag.FromErr(err)
+	}
+	repoKey := d.Get("repo_key").(string)
+	projectKey := d.Get("project_key").(string)
+	// assigning rewrites the repository's config, like creating or updating one does
+	unlock, err := lockDescriptor(ctx, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	// force moves the repository even when it already is in another project
+	_, err = m.(*ProviderMetadata).Client.R().SetContext(ctx).
+		SetQueryParam("force", "true").
+		Put(fmt.Sprintf("%s%s/%s", projectRepositoryEndpoint, repoKey, projectKey))
+	unlock()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(repoKey)
+	return resourceProjectRepositoryRead(ctx, d, m)
+}
+
+func resourceProjectRepositoryRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderMetadata).requireAccessToken("artifactory_project_repository"); err != nil {
+		return diag.FromErr(err)
+	}
+	resp, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).Get(repositoriesEndpoint + d.Id())
+	if err != nil {
+		if IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	project := repoProject{}
+	if err := json.Unmarshal(resp.Body(), &project); err != nil {
+		return diag.FromErr(err)
+	}
+	// unassigned out of band, the assignment is gone. Moved to another project shows up as a change of project_key
+	if project.ProjectKey == "" {
+		d.SetId("")
+		return nil
+	}
+	setValue := mkLens(d)
+	setValue("repo_key", d.Id())
+	errors := setValue("project_key", project.ProjectKey)
+	if errors != nil && len(errors) > 0 {
+		return diag.Errorf("failed saving state for project repository %q", errors)
+	}
+	return nil
+}
+
+func resourceProjectRepositoryDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderMetadata).requireAccessToken("artifactory_project_repository"); err != nil {
+		return diag.FromErr(err)
+	}
+	unlock, err := lockDescriptor(ctx, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
+	_, err = m.(*ProviderMetadata).Client.R().SetContext(ctx).Delete(projectRepositoryEndpoint + d.Id())
+	if IsNotFound(err) {
+		return nil
+	}
+	return diag.FromErr(err)
+}
@@ -0,0 +1,113 @@
+package artifactory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAccProjectRepository(t *testing.T) {
+	_, fqrn, name := mkNames("proj", "artifactory_project_repository")
+	temp := `
+		resource "artifactory_project" "{{ .name }}" {
+			key          = "{{ .name }}"
+			display_name = "{{ .name }}"
+
+			admin_privileges {
+				manage_members   = true
+				manage_resources = true
+				index_resources  = true
+			}
+		}
+
+		resource "artifactory_local_repository" "{{ .name }}" {
+			key          = "{{ .name }}-generic-local"
+			package_type = "generic"
+		}
+
+		resource "artifactory_project_repository" "{{ .name }}" {
+			repo_key    = artifactory_local_repository.{{ .name }}.key
+			project_key = artifactory_project.{{ .name }}.key
+		}
+	`
+	config := executeTemplate(name, temp, map[string]string{"name": name})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		CheckDestroy:      verifyDeleted(fmt.Sprintf("artifactory_project.%s", name), checkProject),
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fqrn, "project_key", name),
+					resource.TestCheckResourceAttr(fqrn, "repo_key", name+"-generic-local"),
+				),
+			},
+			{
+				ResourceName:      fqrn,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestProjectRepositoryOutOfBandMoves(t *testing.T) {
+	meta, client := newFakeMeta(t)
+	client.SetAuthToken("token")
+	ctx := context.Background()
+
+	for _, key := range []string{"first", "second"} {
+		if _, err := client.R().SetBody(Project{Key: key, DisplayName: key}).Post(projectsEndpoint); err != nil {
+			t.Fatal(err)
+		}
+	}
+	repo := resourceArtifactoryLocalRepository()
+	if diags := repo.CreateContext(ctx, schema.TestResourceDataRaw(t, repo.Schema, map[string]interface{}{
+		"key": "assigned", "package_type": "generic",
+	}), meta); diags.HasError() {
+		t.Fatalf("create failed: %v", diags)
+	}
+
+	res := resourceArtifactoryProjectRepository()
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{"repo_key": "assigned", "project_key": "first"})
+	if diags := res.CreateContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("create failed: %v", diags)
+	}
+
+	if _, err := client.R().SetQueryParam("force", "true").Put(projectRepositoryEndpoint + "assigned/second"); err != nil {
+		t.Fatal(err)
+	}
+	if diags := res.ReadContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("read failed: %v", diags)
+	}
+	if d.Get("project_key") != "second" {
+		t.Errorf("expected the move to show up in project_key, got %v", d.Get("project_key"))
+	}
+
+	if _, err := client.R().Delete(projectRepositoryEndpoint + "assigned"); err != nil {
+		t.Fatal(err)
+	}
+	if diags := res.ReadContext(ctx, d, meta); diags.HasError() || d.Id() != "" {
+		t.Errorf("expected an unassigned repository to clear the id, got %q %v", d.Id(), diags)
+	}
+}
+
+func TestProjectRepositoryRequiresVersion(t *testing.T) {
+	client, err := buildResty("http://localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetAuthToken("token")
+	res := resourceArtifactoryProjectRepository()
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{"repo_key": "lib-local", "project_key": "myproj"})
+	diags := res.CreateContext(context.Background(), d, &ProviderMetadata{Client: client, ArtifactoryVersion: "7.10.2"})
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "requires Artifactory") {
+		t.Errorf("expected the assignment to need a newer artifactory, got %v", diags)
+	}
+}
@@ -0,0 +1,201 @@
+package artifactory
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAccProject_full(t *testing.T) {
+	_, fqrn, name := mkNames("proj", "artifactory_project")
+	temp := `
+		resource "artifactory_project" "{{ .name }}" {
+			key                      = "{{ .name }}"
+			display_name             = "{{ .name }}"
+			description              = "test project"
+			max_storage_in_gibibytes = {{ .quota }}
+
+			admin_privileges {
+				manage_members   = true
+				manage_resources = {{ .manageResources }}
+				index_resources  = true
+			}
+
+			member {
+				name  = "admin"
+				roles = [{{ .roles }}]
+			}
+
+			role {
+				name         = "qa"
+				environments = ["DEV"]
+				actions      = ["READ_REPOSITORY", "ANNOTATE_REPOSITORY"]
+			}
+		}
+	`
+	config := executeTemplate(name, temp, map[string]string{
+		"name": name, "quota": "10", "manageResources": "true", "roles": `"Developer", "qa"`,
+	})
+	updated := executeTemplate(name, temp, map[string]string{
+		"name": name, "quota": "-1", "manageResources": "false", "roles": `"Viewer"`,
+	})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		CheckDestroy:      verifyDeleted(fqrn, checkProject),
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fqrn, "key", name),
+					resource.TestCheckResourceAttr(fqrn, "max_storage_in_gibibytes", "10"),
+					resource.TestCheckResourceAttr(fqrn, "admin_privileges.0.manage_resources", "true"),
+					resource.TestCheckResourceAttr(fqrn, "member.#", "1"),
+					resource.TestCheckResourceAttr(fqrn, "role.#", "1"),
+				),
+			},
+			{
+				Config: updated,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fqrn, "max_storage_in_gibibytes", "-1"),
+					resource.TestCheckResourceAttr(fqrn, "admin_privileges.0.manage_resources", "false"),
+				),
+			},
+			{
+				ResourceName:      fqrn,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func checkProject(id string, request *resty.Request) (*resty.Response, error) {
+	return request.AddRetryCondition(neverRetry).Get(projectEndpoint(id))
+}
+
+func TestProjectMembersAndRoles(t *testing.T) {
+	meta, client := newFakeMeta(t)
+	client.SetAuthToken("token")
+	ctx := context.Background()
+	res := resourceArtifactoryProject()
+
+	role := map[string]interface{}{"name": "qa", "environments": []interface{}{"DEV"}, "actions": []interface{}{"READ_REPOSITORY"}}
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{
+		"key":                      "myproj",
+		"display_name":             "My project",
+		"max_storage_in_gibibytes": 2,
+		"admin_privileges": []interface{}{
+			map[string]interface{}{"manage_members": true, "manage_resources": false, "index_resources": true},
+		},
+		"member": []interface{}{map[string]interface{}{"name": "bob", "roles": []interface{}{"qa", "Developer"}}},
+		"group":  []interface{}{map[string]interface{}{"name": "readers", "roles": []interface{}{"Viewer"}}},
+		"role":   []interface{}{role},
+	})
+	if diags := res.CreateContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("create failed: %v", diags)
+	}
+	if d.Get("max_storage_in_gibibytes") != 2 {
+		t.Errorf("expected the quota to round trip, got %v", d.Get("max_storage_in_gibibytes"))
+	}
+	// only the custom role is ours, the predefined ones the project comes with aren't
+	if roles := d.Get("role").(*schema.Set); roles.Len() != 1 {
+		t.Errorf("expected only the custom role in state, got %v", roles.List())
+	}
+	if members := d.Get("member").(*schema.Set); members.Len() != 1 {
+		t.Errorf("expected bob as member, got %v", members.List())
+	}
+
+	// a member removed out of band shows up as drift
+	if _, err := client.R().Delete(projectEndpoint("myproj", "users", "bob")); err != nil {
+		t.Fatal(err)
+	}
+	if diags := res.ReadContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("read failed: %v", diags)
+	}
+	if members := d.Get("member").(*schema.Set); members.Len() != 0 {
+		t.Errorf("expected bob to be gone, got %v", members.List())
+	}
+
+	if diags := res.DeleteContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("delete failed: %v", diags)
+	}
+	if diags := res.ReadContext(ctx, d, meta); diags.HasError() || d.Id() != "" {
+		t.Errorf("expected a deleted project to clear the id, got %q %v", d.Id(), diags)
+	}
+}
+
+func TestProjectRequiresAccessToken(t *testing.T) {
+	client, err := buildResty("http://localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetBasicAuth("admin", "password")
+	res := resourceArtifactoryProject()
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{"key": "myproj"})
+	d.SetId("myproj")
+	meta := &ProviderMetadata{Client: client}
+	ctx := context.Background()
+	for name, diags := range map[string]diag.Diagnostics{
+		"create": res.CreateContext(ctx, d, meta),
+		"read":   res.ReadContext(ctx, d, meta),
+		"update": res.UpdateContext(ctx, d, meta),
+		"delete": res.DeleteContext(ctx, d, meta),
+	} {
+		if !diags.HasError() {
+			t.Errorf("expected %s of the project to need an access token", name)
+		}
+	}
+}
+
+func TestProjectRequiresVersion(t *testing.T) {
+	client, err := buildResty("http://localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetAuthToken("token")
+	res := resourceArtifactoryProject()
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{"key": "myproj"})
+	d.SetId("myproj")
+	meta := &ProviderMetadata{Client: client, ArtifactoryVersion: "7.10.2"}
+	ctx := context.Background()
+	// read covers import and refresh, which would otherwise get a bare 404
+	for name, diags := range map[string]diag.Diagnostics{
+		"create": res.CreateContext(ctx, d, meta),
+		"read":   res.ReadContext(ctx, d, meta),
+		"update": res.UpdateContext(ctx, d, meta),
+		"delete": res.DeleteContext(ctx, d, meta),
+	} {
+		if !diags.HasError() || !strings.Contains(diags[0].Summary, "requires Artifactory") {
+			t.Errorf("expected %s of the project to need a newer artifactory, got %v", name, diags)
+		}
+	}
+}
+
+func TestProjectStorageQuota(t *testing.T) {
+	validate := resourceArtifactoryProject().Schema["max_storage_in_gibibytes"].ValidateFunc
+	for quota, valid := range map[int]bool{-2: false, -1: true, 0: false, 1: true, 100: true} {
+		if _, errs := validate(quota, "max_storage_in_gibibytes"); (len(errs) == 0) != valid {
+			t.Errorf("expected %d to be valid: %t, got %v", quota, valid, errs)
+		}
+	}
+}
+
+func TestProjectRequiresEnterprise(t *testing.T) {
+	client, err := buildResty("http://localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetAuthToken("token")
+	d := schema.TestResourceDataRaw(t, resourceArtifactoryProject().Schema, map[string]interface{}{"key": "myproj"})
+	diags := resourceProjectCreate(context.Background(), d, &ProviderMetadata{Client: client, LicenseType: "Commercial"})
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "requires an Enterprise") {
+		t.Errorf("expected the project to need an enterprise license, got %v", diags)
+	}
+}
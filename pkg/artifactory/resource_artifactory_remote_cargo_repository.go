@@ -37,18 +37,19 @@ var cargoRemoteRepoReadFun = mkRepoRead(packCargoRemoteRepo, func() interface{}
 })
 
 func resourceArtifactoryRemoteCargoRepository() *schema.Resource {
-	return &schema.Resource{
-		Create: mkRepoCreate(unpackCargoRemoteRepo, cargoRemoteRepoReadFun),
-		Read:   cargoRemoteRepoReadFun,
-		Update: mkRepoUpdate(unpackCargoRemoteRepo, cargoRemoteRepoReadFun),
-		Delete: deleteRepo,
-		Exists: repoExists,
+	return upgradeFromVersion0(&schema.Resource{
+		CreateContext: mkRepoCreate(unpackCargoRemoteRepo, cargoRemoteRepoReadFun),
+		ReadContext:   cargoRemoteRepoReadFun,
+		UpdateContext: mkRepoUpdate(unpackCargoRemoteRepo, cargoRemoteRepoReadFun),
+		DeleteContext: deleteRepo,
+		Timeouts:      defaultResourceTimeouts(),
+		CustomizeDiff: defaultProjectKey,
 
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 		Schema: cargoRemoteSchema,
-	}
+	}, upgradeRemoteRepoState)
 }
 
 func unpackCargoRemoteRepo(s *schema.ResourceData) (interface{}, string, error) {
@@ -55,18 +55,19 @@ var dockerRemoteRepoReadFun = mkRepoRead(packDockerRemoteRepo, func() interface{
 })
 
 func resourceArtifactoryRemoteDockerRepository() *schema.Resource {
-	return &schema.Resource{
-		Create: mkRepoCreate(unpackDockerRemoteRepo, dockerRemoteRepoReadFun),
-		Read:   dockerRemoteRepoReadFun,
-		Update: mkRepoUpdate(unpackDockerRemoteRepo, dockerRemoteRepoReadFun),
-		Delete: deleteRepo,
-		Exists: repoExists,
+	return upgradeFromVersion0(&schema.Resource{
+		CreateContext: mkRepoCreate(unpackDockerRemoteRepo, dockerRemoteRepoReadFun),
+		ReadContext:   dockerRemoteRepoReadFun,
+		UpdateContext: mkRepoUpdate(unpackDockerRemoteRepo, dockerRemoteRepoReadFun),
+		DeleteContext: deleteRepo,
+		Timeouts:      defaultResourceTimeouts(),
+		CustomizeDiff: defaultProjectKey,
 
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 		Schema: dockerRemoteSchema,
-	}
+	}, upgradeRemoteRepoState)
 }
 
 func unpackDockerRemoteRepo(s *schema.ResourceData) (interface{}, string, error) {
@@ -31,18 +31,19 @@ var helmRemoteRepoReadFun = mkRepoRead(packhelmRemoteRepo, func() interface{} {
 })
 
 func resourceArtifactoryRemoteHelmRepository() *schema.Resource {
-	return &schema.Resource{
-		Create: mkRepoCreate(unpackhelmRemoteRepo, helmRemoteRepoReadFun),
-		Read:   helmRemoteRepoReadFun,
-		Update: mkRepoUpdate(unpackhelmRemoteRepo, helmRemoteRepoReadFun),
-		Delete: deleteRepo,
-		Exists: repoExists,
+	return upgradeFromVersion0(&schema.Resource{
+		CreateContext: mkRepoCreate(unpackhelmRemoteRepo, helmRemoteRepoReadFun),
+		ReadContext:   helmRemoteRepoReadFun,
+		UpdateContext: mkRepoUpdate(unpackhelmRemoteRepo, helmRemoteRepoReadFun),
+		DeleteContext: deleteRepo,
+		Timeouts:      defaultResourceTimeouts(),
+		CustomizeDiff: defaultProjectKey,
 
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 		Schema: helmRemoteSchema,
-	}
+	}, upgradeRemoteRepoState)
 }
 
 func unpackhelmRemoteRepo(s *schema.ResourceData) (interface{}, string, error) {
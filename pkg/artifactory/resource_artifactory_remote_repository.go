@@ -25,18 +25,22 @@ var legacyRemoteRepoReadFun = mkRepoRead(packLegacyRemoteRepo, func() interface{
 })
 
 func resourceArtifactoryRemoteRepository() *schema.Resource {
-	return &schema.Resource{
-		Create: mkRepoCreate(unpackLegacyRemoteRepo, legacyRemoteRepoReadFun),
-		Read:   legacyRemoteRepoReadFun,
-		Update: mkRepoUpdate(unpackLegacyRemoteRepo, legacyRemoteRepoReadFun),
-		Delete: deleteRepo,
-		Exists: repoExists,
+	return upgradeFromVersion0(&schema.Resource{
+		CreateContext: mkRepoCreate(unpackLegacyRemoteRepo, legacyRemoteRepoReadFun),
+		ReadContext:   legacyRemoteRepoReadFun,
+		UpdateContext: mkRepoUpdate(unpackLegacyRemoteRepo, legacyRemoteRepoReadFun),
+		DeleteContext: deleteRepo,
+		Timeouts:      defaultResourceTimeouts(),
+		CustomizeDiff: defaultProjectKey,
 
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
+			"project_key":              repoProjectKeySchema,
+			"skip_default_project_key": skipDefaultProjectKeySchema,
+			"project_environments":     repoProjectEnvironmentsSchema,
 			"key": {
 				Type:         schema.TypeString,
 				Required:     true,
@@ -316,7 +320,7 @@ func resourceArtifactoryRemoteRepository() *schema.Resource {
 				},
 			},
 		},
-	}
+	}, upgradeRemoteRepoState)
 }
 
 func unpackLegacyRemoteRepo(s *schema.ResourceData) (interface{}, string, error) {
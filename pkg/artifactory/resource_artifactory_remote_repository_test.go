@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func TestAccLocalAllowDotsUnderscorersAndDashesInKeyGH129(t *testing.T) {
@@ -451,3 +452,23 @@ func TestAccRemoteRepository_generic_with_propagate(t *testing.T) {
 		},
 	})
 }
+
+func TestUpgradeRemoteRepoState(t *testing.T) {
+	for _, res := range []*schema.Resource{resourceArtifactoryRemoteRepository(), resourceArtifactoryRemoteDockerRepository()} {
+		state := upgradeState(t, res, `{
+			"id": "remote-test",
+			"key": "remote-test",
+			"package_type": "docker",
+			"url": "https://registry-1.docker.io/",
+			"username": "bob",
+			"password": "hunter2"
+		}`)
+		if state["password"] != getMD5Hash("hunter2") {
+			t.Errorf("expected the plain text password to be hashed, got %v", state["password"])
+		}
+		state = upgradeState(t, res, fmt.Sprintf(`{"id": "remote-test", "key": "remote-test", "password": %q}`, getMD5Hash("hunter2")))
+		if state["password"] != getMD5Hash("hunter2") {
+			t.Errorf("an already hashed password was hashed again: %v", state["password"])
+		}
+	}
+}
@@ -1,10 +1,10 @@
 package artifactory
 
 import (
+	"context"
 	"fmt"
 
-	"github.com/go-resty/resty/v2"
-
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/jfrog/jfrog-client-go/artifactory/services/utils"
 
@@ -94,19 +94,31 @@ var replicationSchema = map[string]*schema.Schema{
 }
 
 func resourceArtifactoryReplicationConfig() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceReplicationConfigCreate,
-		Read:   resourceReplicationConfigRead,
-		Update: resourceReplicationConfigUpdate,
-		Delete: resourceReplicationConfigDelete,
-		Exists: resourceReplicationConfigExists,
+	return upgradeFromVersion0(&schema.Resource{
+		CreateContext: resourceReplicationConfigCreate,
+		ReadContext:   resourceReplicationConfigRead,
+		UpdateContext: resourceReplicationConfigUpdate,
+		DeleteContext: resourceReplicationConfigDelete,
+		Timeouts:      defaultResourceTimeouts(),
 
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: mergeSchema(replicationSchemaCommon, repMultipleSchema),
+	}, upgradeReplicationConfigState)
+}
+
+// upgradeReplicationConfigState hashes the passwords of replications written to state before they were hashed
+func upgradeReplicationConfigState(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	if replications, ok := rawState["replications"].([]interface{}); ok {
+		for _, replication := range replications {
+			if r, ok := replication.(map[string]interface{}); ok {
+				hashPasswordInState(r)
+			}
+		}
 	}
+	return rawState, nil
 }
 
 func unpackReplicationConfig(s *schema.ResourceData) ReplicationConfig {
@@ -212,25 +224,39 @@ func packReplicationConfig(replicationConfig *ReplicationConfig, d *schema.Resou
 	return nil
 }
 
-func resourceReplicationConfigCreate(d *schema.ResourceData, m interface{}) error {
+func resourceReplicationConfigCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	replicationConfig := unpackReplicationConfig(d)
 
-	_, err := m.(*resty.Client).R().SetBody(replicationConfig).Put("artifactory/api/replications/multiple/" + replicationConfig.RepoKey)
+	// multi-push replication is an enterprise feature. Anything else gets a 400 with no explanation
+	if err := m.(*ProviderMetadata).requireEnterprise("artifactory_replication_config"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	unlock, err := lockDescriptor(ctx, m)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
+	}
+	_, err = m.(*ProviderMetadata).Client.R().SetContext(ctx).SetBody(replicationConfig).Put("artifactory/api/replications/multiple/" + replicationConfig.RepoKey)
+	unlock()
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	d.SetId(replicationConfig.RepoKey)
-	return resourceReplicationConfigRead(d, m)
+	return resourceReplicationConfigRead(ctx, d, m)
 }
 
-func resourceReplicationConfigRead(d *schema.ResourceData, m interface{}) error {
-	c := m.(*resty.Client)
+func resourceReplicationConfigRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ProviderMetadata).Client
 	var replications []utils.ReplicationBody
-	_, err := c.R().SetResult(&replications).Get("artifactory/api/replications/" + d.Id())
+	_, err := c.R().SetContext(ctx).SetResult(&replications).Get("artifactory/api/replications/" + d.Id())
 
 	if err != nil {
-		return err
+		if IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
 	}
 
 	repConfig := ReplicationConfig{
@@ -241,31 +267,44 @@ func resourceReplicationConfigRead(d *schema.ResourceData, m interface{}) error
 		repConfig.EnableEventReplication = replications[0].EnableEventReplication
 		repConfig.CronExp = replications[0].CronExp
 	}
-	return packReplicationConfig(&repConfig, d)
+	return diag.FromErr(packReplicationConfig(&repConfig, d))
 }
 
-func resourceReplicationConfigUpdate(d *schema.ResourceData, m interface{}) error {
+func resourceReplicationConfigUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	replicationConfig := unpackReplicationConfig(d)
 
-	_, err := m.(*resty.Client).R().SetBody(replicationConfig).Post("/api/replications/" + d.Id())
+	if err := m.(*ProviderMetadata).requireEnterprise("artifactory_replication_config"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	unlock, err := lockDescriptor(ctx, m)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
+	}
+	_, err = m.(*ProviderMetadata).Client.R().SetContext(ctx).SetBody(replicationConfig).Post("/api/replications/" + d.Id())
+	unlock()
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	d.SetId(replicationConfig.RepoKey)
 
-	return resourceReplicationConfigRead(d, m)
+	return resourceReplicationConfigRead(ctx, d, m)
 }
 
-func resourceReplicationConfigDelete(d *schema.ResourceData, m interface{}) error {
-	_, err := m.(*resty.Client).R().Delete("artifactory/api/replications/" + d.Id())
-	return err
+func resourceReplicationConfigDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	unlock, err := lockDescriptor(ctx, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	_, err = m.(*ProviderMetadata).Client.R().SetContext(ctx).Delete("artifactory/api/replications/" + d.Id())
+	unlock()
+	if IsNotFound(err) {
+		return nil
+	}
+	return diag.FromErr(err)
 }
 func repConfigExists(id string, m interface{}) (bool, error) {
-	_, err := m.(*resty.Client).R().Head("artifactory/api/replications/" + id)
+	_, err := m.(*ProviderMetadata).Client.R().Head("artifactory/api/replications/" + id)
 	return err == nil, err
 }
-
-func resourceReplicationConfigExists(d *schema.ResourceData, m interface{}) (bool, error) {
-	return repConfigExists(d.Id(), m)
-}
@@ -1,11 +1,15 @@
 package artifactory
 
 import (
+	"context"
 	"fmt"
 	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -128,3 +132,39 @@ func testAccCheckReplicationDestroy(id string) func(*terraform.State) error {
 		return nil
 	}
 }
+
+func TestUpgradeReplicationConfigState(t *testing.T) {
+	state := upgradeState(t, resourceArtifactoryReplicationConfig(), `{
+		"id": "lib-local",
+		"repo_key": "lib-local",
+		"cron_exp": "0 0 * * * ?",
+		"enable_event_replication": true,
+		"replications": [
+			{"url": "http://localhost:8080", "username": "admin", "password": "Passw0rd!", "enabled": true},
+			{"url": "http://localhost:8081", "username": "admin", "password": ""}
+		]
+	}`)
+	replications := state["replications"].([]interface{})
+	if password := replications[0].(map[string]interface{})["password"]; password != getMD5Hash("Passw0rd!") {
+		t.Errorf("expected the plain text password to be hashed, got %v", password)
+	}
+	if password := replications[1].(map[string]interface{})["password"]; password != "" {
+		t.Errorf("an empty password should stay empty, got %v", password)
+	}
+}
+
+func TestReplicationConfigRequiresEnterprise(t *testing.T) {
+	res := resourceArtifactoryReplicationConfig()
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{"repo_key": "lib-local", "cron_exp": "0 0 * * * ?"})
+	d.SetId("lib-local")
+	meta := &ProviderMetadata{LicenseType: "Commercial"}
+	ctx := context.Background()
+	for name, diags := range map[string]diag.Diagnostics{
+		"create": res.CreateContext(ctx, d, meta),
+		"update": res.UpdateContext(ctx, d, meta),
+	} {
+		if !diags.HasError() || !strings.Contains(diags[0].Summary, "requires an Enterprise") {
+			t.Errorf("expected %s of multi-push replication to need an enterprise license, got %v", name, diags)
+		}
+	}
+}
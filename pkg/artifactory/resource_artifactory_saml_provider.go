@@ -0,0 +1,213 @@
+package artifactory
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v2"
+)
+
+// samlProvidersMinVersion is the first artifactory with several saml providers
+const samlProvidersMinVersion = "7.27.0"
+
+// SamlProviderSecurity is the yaml patch for a single saml provider. Like the ldap settings, the providers are a map
+// keyed by name, so a patch only touches the provider it names and two IdPs can be configured side by side
+type SamlProviderSecurity struct {
+	SamlProviders SamlProvidersWrapper `yaml:"security"`
+}
+
+type SamlProvidersWrapper struct {
+	Providers map[string]*SamlProvider `yaml:"samlProvidersSettings"`
+}
+
+type SamlProvider struct {
+	Name                      string `yaml:"-" xml:"name"`
+	EnableIntegration         bool   `yaml:"enableIntegration" xml:"enableIntegration"`
+	Certificate               string `yaml:"certificate" xml:"certificate"`
+	LoginUrl                  string `yaml:"loginUrl" xml:"loginUrl"`
+	LogoutUrl                 string `yaml:"logoutUrl" xml:"logoutUrl"`
+	ServiceProviderName       string `yaml:"serviceProviderName" xml:"serviceProviderName"`
+	EmailAttribute            string `yaml:"emailAttribute" xml:"emailAttribute"`
+	GroupAttribute            string `yaml:"groupAttribute" xml:"groupAttribute"`
+	SyncGroups                bool   `yaml:"syncGroups" xml:"syncGroups"`
+	NoAutoUserCreation        bool   `yaml:"noAutoUserCreation" xml:"noAutoUserCreation"`
+	AllowUserToAccessProfile  bool   `yaml:"allowUserToAccessProfile" xml:"allowUserToAccessProfile"`
+	AutoRedirect              bool   `yaml:"autoRedirect" xml:"autoRedirect"`
+	VerifyAudienceRestriction bool   `yaml:"verifyAudienceRestriction" xml:"verifyAudienceRestriction"`
+}
+
+// SamlProviderConfiguration is the part of artifactory.config.xml with the saml providers
+type SamlProviderConfiguration struct {
+	Providers []SamlProvider `xml:"security>samlProvidersSettings>samlProviderSetting"`
+}
+
+func resourceArtifactorySamlProvider() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceSamlProviderUpdate,
+		ReadContext:   resourceSamlProviderRead,
+		UpdateContext: resourceSamlProviderUpdate,
+		DeleteContext: resourceSamlProviderDelete,
+		Timeouts:      defaultResourceTimeouts(),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"enable": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"certificate": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateSamlCertificate,
+				DiffSuppressFunc: sameCertificate,
+				Description:      "The IdP's certificate, PEM or only its base64",
+			},
+			"login_url": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"logout_url": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"service_provider_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The entity id artifactory is registered as with the IdP",
+			},
+			"email_attribute": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The attribute of the assertion with the user's email",
+			},
+			"group_attribute": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The attribute of the assertion with the user's groups, with sync_groups",
+			},
+			"sync_groups": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"no_auto_user_creation": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"allow_user_to_access_profile": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"auto_redirect": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"verify_audience_restriction": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+		},
+	}
+}
+
+func unpackSamlProvider(s *schema.ResourceData) *SamlProvider {
+	d := &ResourceData{s}
+	return &SamlProvider{
+		Name:                      d.getString("name", false),
+		EnableIntegration:         d.getBool("enable", false),
+		Certificate:               d.getString("certificate", false),
+		LoginUrl:                  d.getString("login_url", false),
+		LogoutUrl:                 d.getString("logout_url", false),
+		ServiceProviderName:       d.getString("service_provider_name", false),
+		EmailAttribute:            d.getString("email_attribute", false),
+		GroupAttribute:            d.getString("group_attribute", false),
+		SyncGroups:                d.getBool("sync_groups", false),
+		NoAutoUserCreation:        d.getBool("no_auto_user_creation", false),
+		AllowUserToAccessProfile:  d.getBool("allow_user_to_access_profile", false),
+		AutoRedirect:              d.getBool("auto_redirect", false),
+		VerifyAudienceRestriction: d.getBool("verify_audience_restriction", false),
+	}
+}
+
+func packSamlProvider(provider *SamlProvider, d *schema.ResourceData) diag.Diagnostics {
+	setValue := mkLens(d)
+
+	setValue("name", provider.Name)
+	setValue("enable", provider.EnableIntegration)
+	setValue("certificate", provider.Certificate)
+	setValue("login_url", provider.LoginUrl)
+	setValue("logout_url", provider.LogoutUrl)
+	setValue("service_provider_name", provider.ServiceProviderName)
+	setValue("email_attribute", provider.EmailAttribute)
+	setValue("group_attribute", provider.GroupAttribute)
+	setValue("sync_groups", provider.SyncGroups)
+	setValue("no_auto_user_creation", provider.NoAutoUserCreation)
+	setValue("allow_user_to_access_profile", provider.AllowUserToAccessProfile)
+	setValue("auto_redirect", provider.AutoRedirect)
+	errors := setValue("verify_audience_restriction", provider.VerifyAudienceRestriction)
+
+	if errors != nil && len(errors) > 0 {
+		return diag.Errorf("failed to pack saml provider %q", errors)
+	}
+	return nil
+}
+
+func resourceSamlProviderRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	config := SamlProviderConfiguration{}
+	if err := getConfiguration(ctx, m, &config); err != nil {
+		return diag.Errorf("failed to retrieve the saml providers during Read: %s", err)
+	}
+	for _, provider := range config.Providers {
+		if provider.Name == d.Id() {
+			return packSamlProvider(&provider, d)
+		}
+	}
+	d.SetId("")
+	return nil
+}
+
+func resourceSamlProviderUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderMetadata).requireVersion("artifactory_saml_provider", samlProvidersMinVersion); err != nil {
+		return diag.FromErr(err)
+	}
+	provider := unpackSamlProvider(d)
+	content, err := yaml.Marshal(&SamlProviderSecurity{SamlProvidersWrapper{map[string]*SamlProvider{provider.Name: provider}}})
+	if err != nil {
+		return diag.Errorf("failed to marshal saml provider during Update")
+	}
+
+	err = sendConfigurationPatch(ctx, content, m)
+	if err != nil {
+		return diag.Errorf("failed to send PATCH request to Artifactory during Update: %s", err)
+	}
+
+	d.SetId(provider.Name)
+	return resourceSamlProviderRead(ctx, d, m)
+}
+
+func resourceSamlProviderDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	content, err := yaml.Marshal(&SamlProviderSecurity{SamlProvidersWrapper{map[string]*SamlProvider{d.Id(): nil}}})
+	if err != nil {
+		return diag.Errorf("failed to marshal saml provider during Delete")
+	}
+
+	err = sendConfigurationPatch(ctx, content, m)
+	if err != nil {
+		return diag.Errorf("failed to send PATCH request to Artifactory during Delete: %s", err)
+	}
+	return nil
+}
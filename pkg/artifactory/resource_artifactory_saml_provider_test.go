@@ -0,0 +1,154 @@
+package artifactory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+const samlProviderCertificate = `MIICUjCCAbugAwIBAgIJALRDng3rGeQvMA0GCSqGSIb3DQEBCwUAMEIxCzAJBgNV
+BAYTAlhYMRUwEwYDVQQHDAxEZWZhdWx0IENpdHkxHDAaBgNVBAoME0RlZmF1bHQg
+Q29tcGFueSBMdGQwHhcNMTkwNTE3MTAwMzI2WhcNMjkwNTE0MTAwMzI2WjBCMQsw
+CQYDVQQGEwJYWDEVMBMGA1UEBwwMRGVmYXVsdCBDaXR5MRwwGgYDVQQKDBNEZWZh
+dWx0IENvbXBhbnkgTHRkMIGfMA0GCSqGSIb3DQEBAQUAA4GNADCBiQKBgQDVBRt7
+Ua3j7K2htVRu1tw629ZZZQI35RGm/53ffF/QUUFXk35at+IiwYZGGQbOGuN1pdji
+gki9/Qit/WO/3uadSkGelKOUYD0DIemlhcZt6iPMQq8mYlUkMPZz5Qlj0ldKI3g+
+Q8Tc/6vEeBv/9jrm9Efg/uwc0DjD8B4Ny6xMHQIDAQABo1AwTjAdBgNVHQ4EFgQU
+VrBaHnYLayO2lKIUde8etG0H6owwHwYDVR0jBBgwFoAUVrBaHnYLayO2lKIUde8e
+tG0H6owwDAYDVR0TBAUwAwEB/zANBgkqhkiG9w0BAQsFAAOBgQA4VBFCrbuOsKtY
+uNlSQCBkTXg907iXihZ+Of/2rerS2gfDCUHdz0xbYdlttNjoGVCA+0alt7ugfYpl
+fy5aAfCHLXEgYrlhe6oDtCMSskbkKFTEI/bRqwGMDb+9NO/yh2KLbNueKJz9Vs5V
+GV9pUrgW6c7kLrC9vpHP+47iyQEbnw==`
+
+func TestAccSamlProvider_full(t *testing.T) {
+	_, fqrn, name := mkNames("saml", "artifactory_saml_provider")
+	temp := `
+		resource "artifactory_saml_provider" "{{ .name }}" {
+			name                  = "{{ .name }}"
+			login_url             = "https://idp.example.com/saml/login"
+			logout_url            = "https://idp.example.com/saml/logout"
+			service_provider_name = "artifactory"
+			certificate           = <<EOF
+-----BEGIN CERTIFICATE-----
+{{ .certificate }}
+-----END CERTIFICATE-----
+EOF
+			group_attribute       = "groups"
+			sync_groups           = {{ .syncGroups }}
+		}
+	`
+	params := map[string]string{"name": name, "certificate": samlProviderCertificate, "syncGroups": "true"}
+	config := executeTemplate(name, temp, params)
+	params["syncGroups"] = "false"
+	updated := executeTemplate(name, temp, params)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		CheckDestroy:      testAccSamlProviderDestroy(fqrn),
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fqrn, "login_url", "https://idp.example.com/saml/login"),
+					resource.TestCheckResourceAttr(fqrn, "group_attribute", "groups"),
+					resource.TestCheckResourceAttr(fqrn, "sync_groups", "true"),
+					resource.TestCheckResourceAttr(fqrn, "enable", "true"),
+				),
+			},
+			{
+				Config: updated,
+				Check:  resource.TestCheckResourceAttr(fqrn, "sync_groups", "false"),
+			},
+			{
+				ResourceName:      fqrn,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccSamlProviderDestroy(id string) func(*terraform.State) error {
+	return func(s *terraform.State) error {
+		provider, _ := testAccProviders["artifactory"]()
+
+		rs, ok := s.RootModule().Resources[id]
+		if !ok {
+			return fmt.Errorf("error: resource id [%s] not found", id)
+		}
+		config := SamlProviderConfiguration{}
+		if err := getConfiguration(context.Background(), provider.Meta(), &config); err != nil {
+			return err
+		}
+		for _, samlProvider := range config.Providers {
+			if samlProvider.Name == rs.Primary.ID {
+				return fmt.Errorf("error: saml provider %s still exists", rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+}
+
+func TestSamlProvidersSideBySide(t *testing.T) {
+	meta, _ := newFakeMeta(t)
+	ctx := context.Background()
+	res := resourceArtifactorySamlProvider()
+
+	create := func(name string) *schema.ResourceData {
+		d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{
+			"name":                  name,
+			"login_url":             "https://" + name + ".example.com/saml/login",
+			"logout_url":            "https://" + name + ".example.com/saml/logout",
+			"service_provider_name": "artifactory",
+			"certificate":           samlProviderCertificate,
+		})
+		if diags := res.CreateContext(ctx, d, meta); diags.HasError() {
+			t.Fatalf("create failed: %v", diags)
+		}
+		return d
+	}
+	old := create("old-idp")
+	current := create("new-idp")
+
+	assert.Equal(t, "https://new-idp.example.com/saml/login", current.Get("login_url"))
+	assert.Equal(t, true, current.Get("enable"))
+
+	if diags := res.DeleteContext(ctx, old, meta); diags.HasError() {
+		t.Fatalf("delete failed: %v", diags)
+	}
+	if diags := res.ReadContext(ctx, old, meta); diags.HasError() || old.Id() != "" {
+		t.Errorf("expected a deleted provider to clear the id, got %q %v", old.Id(), diags)
+	}
+	if diags := res.ReadContext(ctx, current, meta); diags.HasError() || current.Id() == "" {
+		t.Errorf("expected new-idp to outlive the delete of old-idp, got %v", diags)
+	}
+}
+
+func TestValidateSamlCertificate(t *testing.T) {
+	pem := "-----BEGIN CERTIFICATE-----\n" + samlProviderCertificate + "\n-----END CERTIFICATE-----\n"
+	assert.False(t, validateSamlCertificate(pem, cty.Path{}).HasError(), "a PEM certificate is valid")
+	assert.False(t, validateSamlCertificate(samlProviderCertificate, cty.Path{}).HasError(), "only the base64 is valid")
+	assert.True(t, validateSamlCertificate("not a certificate", cty.Path{}).HasError())
+	assert.True(t, validateSamlCertificate("", cty.Path{}).HasError())
+}
+
+func TestSamlProviderRequiresVersion(t *testing.T) {
+	client, err := buildResty("http://localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := resourceArtifactorySamlProvider()
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{"name": "okta"})
+	diags := res.CreateContext(context.Background(), d, &ProviderMetadata{Client: client, ArtifactoryVersion: "7.10.2"})
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "requires Artifactory") {
+		t.Errorf("expected the saml provider to need a newer artifactory, got %v", diags)
+	}
+}
@@ -1,9 +1,13 @@
 package artifactory
 
 import (
+	"bytes"
 	"context"
+	"crypto/x509"
+	"fmt"
+	"strings"
 
-	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -39,9 +43,10 @@ func resourceArtifactorySamlSettings() *schema.Resource {
 		CreateContext: resourceSamlSettingsUpdate,
 		DeleteContext: resourceSamlSettingsDelete,
 		ReadContext:   resourceSamlSettingsRead,
+		Timeouts:      defaultResourceTimeouts(),
 
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -51,8 +56,11 @@ func resourceArtifactorySamlSettings() *schema.Resource {
 				Default:  true,
 			},
 			"certificate": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateSamlCertificate,
+				DiffSuppressFunc: sameCertificate,
+				Description:      "PEM, or only its base64. Line breaks and the BEGIN/END lines don't matter",
 			},
 			"email_attribute": {
 				Type:     schema.TypeString,
@@ -103,14 +111,44 @@ func resourceArtifactorySamlSettings() *schema.Resource {
 	}
 }
 
-func resourceSamlSettingsRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*resty.Client)
+// parseSamlCertificate parses the certificate the way artifactory_certificate does, after wrapping one that's only
+// the base64 in BEGIN/END lines. That base64, on one line, is all artifactory keeps of it
+func parseSamlCertificate(certificate string) (*x509.Certificate, error) {
+	if !strings.Contains(certificate, "-----BEGIN") {
+		certificate = fmt.Sprintf("-----BEGIN CERTIFICATE-----\n%s\n-----END CERTIFICATE-----\n",
+			strings.Join(strings.Fields(certificate), ""))
+	}
+	return extractCertificate(certificate)
+}
+
+func validateSamlCertificate(value interface{}, _ cty.Path) diag.Diagnostics {
+	if _, err := parseSamlCertificate(value.(string)); err != nil {
+		return diag.Errorf("certificate is not a valid X.509 certificate: %s", err)
+	}
+	return nil
+}
+
+// sameCertificate compares what the certificates decode to, so neither the BEGIN/END lines nor line breaks make a diff
+func sameCertificate(_, old, new string, _ *schema.ResourceData) bool {
+	oldCertificate, err := parseSamlCertificate(old)
+	if err != nil {
+		return false
+	}
+	newCertificate, err := parseSamlCertificate(new)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(oldCertificate.Raw, newCertificate.Raw)
+}
+
+func resourceSamlSettingsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*ProviderMetadata).Client
 
 	samlSettings := SamlSettings{}
 
-	_, err := c.R().SetResult(&samlSettings).Get("artifactory/api/saml/config")
+	_, err := c.R().SetContext(ctx).SetResult(&samlSettings).Get("artifactory/api/saml/config")
 	if err != nil {
-		return diag.Errorf("failed to retrieve data from <base_url>/artifactory/api/saml/config during Read")
+		return diag.Errorf("failed to retrieve data from <base_url>/artifactory/api/saml/config during Read: %s", err)
 	}
 
 	s := SamlSecurity{SamlSettingsWrapper{Settings: samlSettings}}
@@ -135,9 +173,9 @@ func resourceSamlSettingsUpdate(ctx context.Context, d *schema.ResourceData, m i
 		return diag.Errorf("failed to marshal saml settings during Update")
 	}
 
-	err = sendConfigurationPatch(content, m)
+	err = sendConfigurationPatch(ctx, content, m)
 	if err != nil {
-		return diag.Errorf("failed to send PATCH request to Artifactory during Update")
+		return diag.Errorf("failed to send PATCH request to Artifactory during Update: %s", err)
 	}
 
 	// we should only have one saml settings resource, using same id
@@ -145,15 +183,15 @@ func resourceSamlSettingsUpdate(ctx context.Context, d *schema.ResourceData, m i
 	return resourceSamlSettingsRead(ctx, d, m)
 }
 
-func resourceSamlSettingsDelete(_ context.Context, _ *schema.ResourceData, m interface{}) diag.Diagnostics {
+func resourceSamlSettingsDelete(ctx context.Context, _ *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var content = `
 security:
   samlSettings: ~
 `
 
-	err := sendConfigurationPatch([]byte(content), m)
+	err := sendConfigurationPatch(ctx, []byte(content), m)
 	if err != nil {
-		return diag.Errorf("failed to send PATCH request to Artifactory during Delete")
+		return diag.Errorf("failed to send PATCH request to Artifactory during Delete: %s", err)
 	}
 
 	return nil
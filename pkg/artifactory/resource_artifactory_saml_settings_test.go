@@ -1,20 +1,26 @@
 package artifactory
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"testing"
 
-	"github.com/go-resty/resty/v2"
-
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
 
+// samlCertificate is only the base64, on one line, the way artifactory keeps it
+const samlCertificate = "MIIBjjCCATWgAwIBAgIUNp7m++cS7h8pBWpgnfG7EOPYH68wCgYIKoZIzj0EAwIwHDEaMBgGA1UEAwwRaWRwLWEuZXhhbXBsZS5jb20wIBcNMjYxMDE4MjMyOTUxWhgPMjEyNjA5MjQyMzI5NTFaMBwxGjAYBgNVBAMMEWlkcC1hLmV4YW1wbGUuY29tMFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAEvZ0JBUhUg4nsEgn1JLg09fgeEP4wAwA+Xsx/xwXeiTyNMk5ziIZdbgf+TBcBUgRWjzqDFQvke1+vZORqoFRizqNTMFEwHQYDVR0OBBYEFNLapvqTaZlIxIC9b6IfMLsKVrgIMB8GA1UdIwQYMBaAFNLapvqTaZlIxIC9b6IfMLsKVrgIMA8GA1UdEwEB/wQFMAMBAf8wCgYIKoZIzj0EAwIDRwAwRAIgCDPUQJUWGS0YpPEyNacUi0n4PxYK5HeA+Xki4sx4U2cCIBzfZHkDPXM/NxgCRR+rGEWIqGcc7+BFcxNb1p2kg2Iq"
+
+const otherSamlCertificate = "MIIBjjCCATWgAwIBAgIUQfNPqNKeT1NyL5tl14f664HSPycwCgYIKoZIzj0EAwIwHDEaMBgGA1UEAwwRaWRwLWIuZXhhbXBsZS5jb20wIBcNMjYxMDE4MjMyOTUxWhgPMjEyNjA5MjQyMzI5NTFaMBwxGjAYBgNVBAMMEWlkcC1iLmV4YW1wbGUuY29tMFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAEQlX2b7A4CX6TyyaT1OOBjmxJsnqMctmilZwllE6/d0ufhQZ83T4pJM38Aoul1J725bqibFxLSQC4vozuV38/s6NTMFEwHQYDVR0OBBYEFKWGLn0LeBH3tpl4kOguS29sla9WMB8GA1UdIwQYMBaAFKWGLn0LeBH3tpl4kOguS29sla9WMA8GA1UdEwEB/wQFMAMBAf8wCgYIKoZIzj0EAwIDRwAwRAIgXVIF1bZsGNwu51CiiXSBOTdQHduLquySoJitddK+WaMCIG2o+jou3bZAHfTl8noXXZdeAcfBGtsgH5f3loIHy0aX"
+
 const SamlSettingsTemplateFull = `
 resource "artifactory_saml_settings" "saml" {
 	enable 					     = true
-	certificate                  = "test-certificate"
+	certificate                  = "` + samlCertificate + `"
 	email_attribute              = "email"
 	group_attribute              = "group"
 	login_url                    = "test-login-url"
@@ -37,7 +43,7 @@ func TestAccSamlSettings_full(t *testing.T) {
 				Config: SamlSettingsTemplateFull,
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("artifactory_saml_settings.saml", "enable", "true"),
-					resource.TestCheckResourceAttr("artifactory_saml_settings.saml", "certificate", "test-certificate"),
+					resource.TestCheckResourceAttr("artifactory_saml_settings.saml", "certificate", samlCertificate),
 					resource.TestCheckResourceAttr("artifactory_saml_settings.saml", "email_attribute", "email"),
 					resource.TestCheckResourceAttr("artifactory_saml_settings.saml", "group_attribute", "group"),
 					resource.TestCheckResourceAttr("artifactory_saml_settings.saml", "login_url", "test-login-url"),
@@ -58,7 +64,7 @@ func testAccSamlSettingsDestroy(id string) func(*terraform.State) error {
 	return func(s *terraform.State) error {
 		provider, _ := testAccProviders["artifactory"]()
 
-		c := provider.Meta().(*resty.Client)
+		c := provider.Meta().(*ProviderMetadata).Client
 
 		_, ok := s.RootModule().Resources[id]
 		if !ok {
@@ -87,3 +93,66 @@ func testAccSamlSettingsDestroy(id string) func(*terraform.State) error {
 		return nil
 	}
 }
+
+// breakBase64 breaks base64 every 64 characters with lineBreak, as in PEM
+func breakBase64(base64 string, lineBreak string) string {
+	var lines []string
+	for len(base64) > 64 {
+		lines = append(lines, base64[:64])
+		base64 = base64[64:]
+	}
+	return strings.Join(append(lines, base64), lineBreak)
+}
+
+func TestSamlCertificateNormalization(t *testing.T) {
+	meta, _ := newFakeMeta(t)
+	ctx := context.Background()
+	res := resourceArtifactorySamlSettings()
+
+	raw := map[string]interface{}{
+		"login_url":             "test-login-url",
+		"logout_url":            "test-logout-url",
+		"service_provider_name": "okta",
+		"certificate":           "-----BEGIN CERTIFICATE-----\n" + breakBase64(samlCertificate, "\n") + "\n-----END CERTIFICATE-----\n",
+	}
+	changedCertificate := func(certificate string) bool {
+		patch := fmt.Sprintf("security:\n  samlSettings:\n    certificate: %q\n", certificate)
+		if err := sendConfigurationPatch(ctx, []byte(patch), meta); err != nil {
+			t.Fatal(err)
+		}
+		state, diags := res.RefreshWithoutUpgrade(ctx, &terraform.InstanceState{ID: "saml_settings", Attributes: map[string]string{"id": "saml_settings"}}, meta)
+		if diags.HasError() {
+			t.Fatalf("read failed: %v", diags)
+		}
+		diff, err := res.Diff(ctx, state, terraform.NewResourceConfigRaw(raw), meta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return diff != nil && diff.Attributes["certificate"] != nil
+	}
+
+	// artifactory keeps only the base64, on one line
+	if changedCertificate(samlCertificate) {
+		t.Error("expected the same certificate without its BEGIN/END lines to be no change")
+	}
+	if changedCertificate("  " + breakBase64(samlCertificate, "\r\n  ") + "  ") {
+		t.Error("expected the same certificate broken differently to be no change")
+	}
+	if !changedCertificate(otherSamlCertificate) {
+		t.Error("expected a different certificate to show up as a diff")
+	}
+	if !changedCertificate("not a certificate") {
+		t.Error("expected a certificate that doesn't parse to show up as a diff")
+	}
+}
+
+func TestValidateSamlSettingsCertificate(t *testing.T) {
+	validate := resourceArtifactorySamlSettings().Schema["certificate"].ValidateDiagFunc
+	if diags := validate(samlCertificate, cty.Path{}); diags.HasError() {
+		t.Errorf("expected the certificate to be valid, got %v", diags)
+	}
+	// only stripping the BEGIN/END lines and white space would have let this through
+	if diags := validate("-----BEGIN CERTIFICATE-----\nbm90IGEgY2VydGlmaWNhdGU=\n-----END CERTIFICATE-----\n", cty.Path{}); !diags.HasError() {
+		t.Error("expected base64 that isn't a certificate to be rejected")
+	}
+}
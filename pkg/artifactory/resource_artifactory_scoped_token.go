@@ -0,0 +1,321 @@
+package artifactory
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const accessTokensEndpoint = "access/api/v1/tokens"
+
+// accessTokenEndpoint is the path of one token, by its id
+func accessTokenEndpoint(id string) string {
+	return accessTokensEndpoint + "/" + id
+}
+
+// scopedTokensMinVersion is the first artifactory with the Access tokens api
+const scopedTokensMinVersion = "7.21.1"
+
+// ScopedTokenRequest is the body of a create on the access tokens api. Unlike the legacy api it's JSON, and the
+// scopes are the applied-permissions ones
+type ScopedTokenRequest struct {
+	GrantType             string `json:"grant_type"`
+	Username              string `json:"username,omitempty"`
+	Scope                 string `json:"scope,omitempty"`
+	ExpiresIn             *int   `json:"expires_in,omitempty"`
+	Refreshable           bool   `json:"refreshable"`
+	Description           string `json:"description,omitempty"`
+	Audience              string `json:"audience,omitempty"`
+	ProjectKey            string `json:"project_key,omitempty"`
+	IncludeReferenceToken bool   `json:"include_reference_token"`
+}
+
+// ScopedTokenRefresh trades the refresh token of a token for a new token with the same scopes and lifetime. Access
+// revokes the old one
+type ScopedTokenRefresh struct {
+	GrantType    string `json:"grant_type"`
+	RefreshToken string `json:"refresh_token"`
+	AccessToken  string `json:"access_token"`
+}
+
+type ScopedToken struct {
+	TokenId        string `json:"token_id"`
+	AccessToken    string `json:"access_token"`
+	RefreshToken   string `json:"refresh_token"`
+	ReferenceToken string `json:"reference_token"`
+	ExpiresIn      int    `json:"expires_in"`
+	Scope          string `json:"scope"`
+	TokenType      string `json:"token_type"`
+}
+
+// ScopedTokenInfo is what access hands back about a token after it's created - never the token itself
+type ScopedTokenInfo struct {
+	TokenId     string `json:"token_id"`
+	Subject     string `json:"subject"`
+	Expiry      int64  `json:"expiry"`
+	IssuedAt    int64  `json:"issued_at"`
+	Issuer      string `json:"issuer"`
+	Description string `json:"description"`
+	Refreshable bool   `json:"refreshable"`
+}
+
+func resourceArtifactoryScopedToken() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceScopedTokenCreate,
+		ReadContext:   resourceScopedTokenRead,
+		UpdateContext: resourceScopedTokenUpdate,
+		DeleteContext: resourceScopedTokenDelete,
+		Timeouts:      defaultResourceTimeouts(),
+		CustomizeDiff: planTokenRotation(scopedTokenExpiry, scopedTokenRefreshable,
+			"access_token", "refresh_token", "reference_token", "expiry", "issued_at"),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"username": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The user the token is for. The provider's user if not set",
+			},
+			"scopes": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+					// access takes them space separated
+					ValidateFunc: validation.StringMatch(regexp.MustCompile(`^\S+$`), "a scope can't contain spaces"),
+				},
+				Set:         schema.HashString,
+				Description: "eg applied-permissions/user, applied-permissions/admin or applied-permissions/groups:readers,deployers. applied-permissions/user if not set",
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"project_key": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateProjectKey,
+			},
+			"audiences": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringMatch(regexp.MustCompile(`^\S+$`), "an audience can't contain spaces"),
+				},
+				Set:         schema.HashString,
+				Description: "Service ids that should accept the token, eg jfrt@* for every artifactory",
+			},
+			"expires_in": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Seconds until the token expires. 0 for a token that never does. Access' default if not set",
+			},
+			"refreshable": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				ForceNew: true,
+			},
+			"include_reference_token": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				ForceNew: true,
+			},
+			"rotate_before": rotateBeforeSchema,
+			"access_token": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"refresh_token": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"reference_token": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"token_type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"subject": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"expiry": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "When the token expires, in RFC 3339. Empty for a token that doesn't",
+			},
+			"issued_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"issuer": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func scopedTokenExpiry(d *schema.ResourceDiff) string {
+	return d.Get("expiry").(string)
+}
+
+// scopedTokenRefreshable is false for an imported token as well, whose refresh token was never seen
+func scopedTokenRefreshable(d *schema.ResourceDiff) bool {
+	return d.Get("refreshable").(bool) && d.Get("refresh_token").(string) != ""
+}
+
+func unpackScopedToken(s *schema.ResourceData) ScopedTokenRequest {
+	d := &ResourceData{s}
+	request := ScopedTokenRequest{
+		GrantType:             "client_credentials",
+		Username:              d.getString("username", false),
+		Scope:                 strings.Join(d.getSet("scopes"), " "),
+		Refreshable:           d.getBool("refreshable", false),
+		Description:           d.getString("description", false),
+		Audience:              strings.Join(d.getSet("audiences"), " "),
+		ProjectKey:            d.getString("project_key", false),
+		IncludeReferenceToken: d.getBool("include_reference_token", false),
+	}
+	// 0 is a token that never expires, not the default
+	if v, ok := d.GetOkExists("expires_in"); ok {
+		expiresIn := v.(int)
+		request.ExpiresIn = &expiresIn
+	}
+	return request
+}
+
+func resourceScopedTokenCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderMetadata).requireAccessToken("artifactory_scoped_token"); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := m.(*ProviderMetadata).requireVersion("artifactory_scoped_token", scopedTokensMinVersion); err != nil {
+		return diag.FromErr(err)
+	}
+	token := ScopedToken{}
+	_, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).SetBody(unpackScopedToken(d)).SetResult(&token).Post(accessTokensEndpoint)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := saveScopedToken(d, token); err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceScopedTokenRead(ctx, d, m)
+}
+
+// resourceScopedTokenUpdate refreshes a token planTokenRotation found due for rotation. Anything else is only
+// rotate_before changing
+func resourceScopedTokenUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if !d.HasChange("access_token") {
+		return resourceScopedTokenRead(ctx, d, m)
+	}
+	oldAccessToken, _ := d.GetChange("access_token")
+	oldRefreshToken, _ := d.GetChange("refresh_token")
+	refresh := ScopedTokenRefresh{
+		GrantType:    "refresh_token",
+		RefreshToken: oldRefreshToken.(string),
+		AccessToken:  oldAccessToken.(string),
+	}
+	token := ScopedToken{}
+	_, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).SetBody(refresh).SetResult(&token).Post(accessTokensEndpoint)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := saveScopedToken(d, token); err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceScopedTokenRead(ctx, d, m)
+}
+
+// saveScopedToken keeps what the create and refresh return. The secrets never come back from anywhere else
+func saveScopedToken(d *schema.ResourceData, token ScopedToken) error {
+	d.SetId(token.TokenId)
+	setValue := mkLens(d)
+	setValue("access_token", token.AccessToken)
+	setValue("refresh_token", token.RefreshToken)
+	setValue("reference_token", token.ReferenceToken)
+	setValue("token_type", token.TokenType)
+	setValue("expires_in", token.ExpiresIn)
+	errors := setValue("scopes", schema.NewSet(schema.HashString, castToInterfaceArr(strings.Fields(token.Scope))))
+	if errors != nil && len(errors) > 0 {
+		return fmt.Errorf("failed saving state for scoped token %q", errors)
+	}
+	return nil
+}
+
+// resourceScopedTokenRead drops a token access doesn't know anymore, which is what happens to revoked ones, and one
+// that has expired
+func resourceScopedTokenRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	info := ScopedTokenInfo{}
+	_, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).SetResult(&info).Get(accessTokenEndpoint(d.Id()))
+	if err != nil {
+		if IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	if info.Expiry > 0 && time.Unix(info.Expiry, 0).Before(time.Now()) {
+		d.SetId("")
+		return nil
+	}
+
+	setValue := mkLens(d)
+	// the subject is jfac@<instance>/users/<username>
+	if username := info.Subject[strings.LastIndex(info.Subject, "/")+1:]; username != "" {
+		setValue("username", username)
+	}
+	setValue("subject", info.Subject)
+	setValue("issuer", info.Issuer)
+	setValue("refreshable", info.Refreshable)
+	setValue("issued_at", formatUnix(info.IssuedAt))
+	errors := setValue("expiry", formatUnix(info.Expiry))
+	if errors != nil && len(errors) > 0 {
+		return diag.Errorf("failed saving state for scoped token %q", errors)
+	}
+	return nil
+}
+
+// resourceScopedTokenDelete revokes the token. Unlike the legacy api, access revokes tokens that expire as well
+func resourceScopedTokenDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	_, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).Delete(accessTokenEndpoint(d.Id()))
+	if IsNotFound(err) {
+		return nil
+	}
+	return diag.FromErr(err)
+}
+
+// formatUnix is a unix timestamp from access in RFC 3339, "" for the 0 it uses for never
+func formatUnix(seconds int64) string {
+	if seconds == 0 {
+		return ""
+	}
+	return time.Unix(seconds, 0).UTC().Format(time.RFC3339)
+}
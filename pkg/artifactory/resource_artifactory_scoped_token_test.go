@@ -0,0 +1,153 @@
+package artifactory
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccScopedToken(t *testing.T) {
+	_, fqrn, name := mkNames("test-scoped-token", "artifactory_scoped_token")
+	temp := `
+		resource "artifactory_scoped_token" "{{ .name }}" {
+			description             = "{{ .name }}"
+			scopes                  = ["applied-permissions/user"]
+			expires_in              = 3600
+			refreshable             = true
+			include_reference_token = true
+		}
+	`
+	config := executeTemplate(name, temp, map[string]string{"name": name})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		CheckDestroy:      verifyDeleted(fqrn, checkScopedToken),
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(fqrn, "access_token"),
+					resource.TestCheckResourceAttrSet(fqrn, "refresh_token"),
+					resource.TestCheckResourceAttrSet(fqrn, "reference_token"),
+					resource.TestCheckResourceAttrSet(fqrn, "expiry"),
+				),
+			},
+		},
+	})
+}
+
+func checkScopedToken(id string, request *resty.Request) (*resty.Response, error) {
+	return request.AddRetryCondition(neverRetry).Get(accessTokenEndpoint(id))
+}
+
+func TestScopedTokenRevocation(t *testing.T) {
+	meta, client := newFakeMeta(t)
+	client.SetAuthToken("token")
+	ctx := context.Background()
+	res := resourceArtifactoryScopedToken()
+
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{
+		"username":                "ci",
+		"scopes":                  []interface{}{"applied-permissions/groups:readers"},
+		"expires_in":              0,
+		"include_reference_token": true,
+	})
+	if diags := res.CreateContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("create failed: %v", diags)
+	}
+	assert.NotEmpty(t, d.Get("access_token"))
+	assert.NotEmpty(t, d.Get("reference_token"))
+	assert.Empty(t, d.Get("expiry"), "a token that never expires has no expiry")
+	assert.Equal(t, "ci", d.Get("username"))
+
+	// revoked in the UI
+	if _, err := client.R().Delete(accessTokenEndpoint(d.Id())); err != nil {
+		t.Fatal(err)
+	}
+	if diags := res.ReadContext(ctx, d, meta); diags.HasError() || d.Id() != "" {
+		t.Errorf("expected a revoked token to leave the state, got %q %v", d.Id(), diags)
+	}
+}
+
+func TestScopedTokenRequiresVersion(t *testing.T) {
+	client, err := buildResty("http://localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetAuthToken("token")
+	res := resourceArtifactoryScopedToken()
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{})
+	diags := res.CreateContext(context.Background(), d, &ProviderMetadata{Client: client, ArtifactoryVersion: "7.10.2"})
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "requires Artifactory") {
+		t.Errorf("expected the scoped token to need a newer artifactory, got %v", diags)
+	}
+}
+
+func TestScopedTokenRotation(t *testing.T) {
+	meta, client := newFakeMeta(t)
+	client.SetAuthToken("token")
+	ctx := context.Background()
+	res := resourceArtifactoryScopedToken()
+
+	raw := map[string]interface{}{
+		"username":      "ci",
+		"expires_in":    3600,
+		"refreshable":   true,
+		"rotate_before": "2h",
+	}
+	d := schema.TestResourceDataRaw(t, res.Schema, raw)
+	if diags := res.CreateContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("create failed: %v", diags)
+	}
+	state := d.State()
+
+	// an hour from expiry, so within the 2h window
+	diff, err := res.Diff(ctx, state, terraform.NewResourceConfigRaw(raw), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff == nil || diff.Attributes["access_token"] == nil || !diff.Attributes["access_token"].NewComputed {
+		t.Fatalf("expected the token to be due for rotation, got %v", diff)
+	}
+	if diff.RequiresNew() {
+		t.Errorf("expected a refreshable token to be refreshed in place, got %v", diff)
+	}
+
+	refreshed, diags := res.Apply(ctx, state, diff, meta)
+	if diags.HasError() {
+		t.Fatalf("refresh failed: %v", diags)
+	}
+	assert.NotEqual(t, state.ID, refreshed.ID, "a refresh issues a new token")
+	assert.NotEqual(t, state.Attributes["access_token"], refreshed.Attributes["access_token"])
+	assert.NotEmpty(t, refreshed.Attributes["refresh_token"])
+	if _, err := checkScopedToken(state.ID, client.R()); !IsNotFound(err) {
+		t.Errorf("expected the refreshed token to be revoked, got %v", err)
+	}
+
+	// without its refresh token the same token can only be replaced
+	state.Attributes["refresh_token"] = ""
+	diff, err = res.Diff(ctx, state, terraform.NewResourceConfigRaw(raw), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff == nil || !diff.RequiresNew() {
+		t.Errorf("expected a token that can't be refreshed to be replaced, got %v", diff)
+	}
+}
+
+func TestTokenRotationDue(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	assert.False(t, tokenRotationDue("", "72h", now), "a token that never expires is never due")
+	assert.False(t, tokenRotationDue("2021-06-10T12:00:00Z", "", now), "nothing is due without rotate_before")
+	assert.False(t, tokenRotationDue("2021-06-10T12:00:00Z", "72h", now))
+	assert.True(t, tokenRotationDue("2021-06-03T12:00:00Z", "72h", now))
+	assert.True(t, tokenRotationDue("2021-05-31T12:00:00Z", "72h", now), "an expired token is due as well")
+}
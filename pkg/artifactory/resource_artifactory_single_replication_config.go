@@ -1,10 +1,11 @@
 package artifactory
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
-	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/jfrog/jfrog-client-go/artifactory/services/utils"
 )
@@ -13,14 +14,14 @@ const replicationEndpoint = "artifactory/api/replications/"
 
 func resourceArtifactorySingleReplicationConfig() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceSingleReplicationConfigCreate,
-		Read:   resourceSingleReplicationConfigRead,
-		Update: resourceSingleReplicationConfigUpdate,
-		Delete: resourceReplicationConfigDelete,
-		Exists: resourceReplicationConfigExists,
+		CreateContext: resourceSingleReplicationConfigCreate,
+		ReadContext:   resourceSingleReplicationConfigRead,
+		UpdateContext: resourceSingleReplicationConfigUpdate,
+		DeleteContext: resourceReplicationConfigDelete,
+		Timeouts:      defaultResourceTimeouts(),
 
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: mergeSchema(replicationSchemaCommon, replicationSchema),
@@ -97,16 +98,21 @@ func packPullReplicationBody(config PullReplication, d *schema.ResourceData) err
 
 	return nil
 }
-func resourceSingleReplicationConfigCreate(d *schema.ResourceData, m interface{}) error {
+func resourceSingleReplicationConfigCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	replicationConfig := unpackSingleReplicationConfig(d)
 	// The password is sent clear
-	_, err := m.(*resty.Client).R().SetBody(replicationConfig).Put(replicationEndpoint + replicationConfig.RepoKey)
+	unlock, err := lockDescriptor(ctx, m)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
+	}
+	_, err = m.(*ProviderMetadata).Client.R().SetContext(ctx).SetBody(replicationConfig).Put(replicationEndpoint + replicationConfig.RepoKey)
+	unlock()
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	d.SetId(replicationConfig.RepoKey)
-	return resourceSingleReplicationConfigRead(d, m)
+	return resourceSingleReplicationConfigRead(ctx, d, m)
 }
 
 // ReplicationSummary this is what you would get if you hit replications/
@@ -136,7 +142,7 @@ type PullReplication struct {
 	CheckBinaryExistenceInFileStore bool   `json:"checkBinaryExistenceInFilestore"`
 }
 
-func resourceSingleReplicationConfigRead(d *schema.ResourceData, m interface{}) error {
+func resourceSingleReplicationConfigRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	// this endpoint serves for both PULL type replications (remote repo) and PUSH type replications
 	// (local repos). In the case of a remote (pull), it's a singular object. In case of local (push), it's an array
 	// If we query replications/ it will tell us which is which, but the direct query does not.
@@ -145,41 +151,50 @@ func resourceSingleReplicationConfigRead(d *schema.ResourceData, m interface{})
 	// an entirely different resource because values like "url" are never available after submit.
 	var result interface{}
 
-	resp, err := m.(*resty.Client).R().SetResult(&result).Get(replicationEndpoint + d.Id())
+	resp, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).SetResult(&result).Get(replicationEndpoint + d.Id())
 	// password comes back scrambled
 	if err != nil {
-		return err
+		if IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
 	}
 
 	switch result.(type) {
 	case []interface{}:
 		if len(result.([]interface{})) > 1 {
-			return fmt.Errorf("resource_single_replication_config does not support multiple replication config on a repo. Use resource_artifactory_replication_config instead")
+			return diag.Errorf("resource_single_replication_config does not support multiple replication config on a repo. Use resource_artifactory_replication_config instead")
 		}
 		var final []utils.ReplicationBody
 		err = json.Unmarshal(resp.Body(), &final)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
-		return packPushReplicationBody(final[0], d)
+		return diag.FromErr(packPushReplicationBody(final[0], d))
 	default:
 		final := PullReplication{}
 		err = json.Unmarshal(resp.Body(), &final)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
-		return packPullReplicationBody(final, d)
+		return diag.FromErr(packPullReplicationBody(final, d))
 	}
 }
 
-func resourceSingleReplicationConfigUpdate(d *schema.ResourceData, m interface{}) error {
+func resourceSingleReplicationConfigUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	replicationConfig := unpackSingleReplicationConfig(d)
-	_, err := m.(*resty.Client).R().SetBody(replicationConfig).Post(replicationEndpoint + replicationConfig.RepoKey)
+	unlock, err := lockDescriptor(ctx, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	_, err = m.(*ProviderMetadata).Client.R().SetContext(ctx).SetBody(replicationConfig).Post(replicationEndpoint + replicationConfig.RepoKey)
+	unlock()
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(replicationConfig.RepoKey)
 
-	return resourceSingleReplicationConfigRead(d, m)
+	return resourceSingleReplicationConfigRead(ctx, d, m)
 }
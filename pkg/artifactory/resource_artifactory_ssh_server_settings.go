@@ -0,0 +1,167 @@
+package artifactory
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"golang.org/x/crypto/ssh"
+	"gopkg.in/yaml.v2"
+)
+
+type SshServerSecurity struct {
+	SshServer SshServerSettingsWrapper `yaml:"security"`
+}
+
+type SshServerSettingsWrapper struct {
+	Settings SshServerSettings `yaml:"sshServerSettings" xml:"sshServerSettings"`
+}
+
+type SshServerSettings struct {
+	EnableSshServer bool   `yaml:"enableSshServer" xml:"enableSshServer"`
+	SshServerPort   int    `yaml:"sshServerPort" xml:"sshServerPort"`
+	CustomUrlBase   string `yaml:"customUrlBase" xml:"customUrlBase"`
+	PublicKey       string `yaml:"publicKey,omitempty" xml:"publicKey"`
+	PrivateKey      string `yaml:"privateKey,omitempty" xml:"privateKey"`
+}
+
+// SshServerConfiguration is the part of artifactory.config.xml with the ssh server settings
+type SshServerConfiguration struct {
+	SshServer SshServerSettingsWrapper `xml:"security"`
+}
+
+func resourceArtifactorySshServerSettings() *schema.Resource {
+	return &schema.Resource{
+		UpdateContext: resourceSshServerSettingsUpdate,
+		CreateContext: resourceSshServerSettingsUpdate,
+		DeleteContext: resourceSshServerSettingsDelete,
+		ReadContext:   resourceSshServerSettingsRead,
+		Timeouts:      defaultResourceTimeouts(),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"enable": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1339,
+				ValidateFunc: validation.IsPortNumber,
+			},
+			"custom_url_base": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The url clients reach the ssh server on, when it's not the base url of artifactory",
+			},
+			"public_key": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateDiagFunc: validateSshPublicKey,
+				DiffSuppressFunc: sameSshPublicKey,
+				RequiredWith:     []string{"private_key"},
+				Description:      "OpenSSH public key of the server. Artifactory generates a key pair when none is set",
+			},
+			"private_key": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Sensitive:        true,
+				StateFunc:        getMD5Hash,
+				ValidateDiagFunc: validateSshPrivateKey,
+				RequiredWith:     []string{"public_key"},
+				Description:      "Unencrypted private key of the server. Artifactory only keeps it encrypted, so only its hash is in state",
+			},
+		},
+	}
+}
+
+func validateSshPrivateKey(value interface{}, _ cty.Path) diag.Diagnostics {
+	if _, err := ssh.ParseRawPrivateKey([]byte(strings.ReplaceAll(value.(string), "\t", ""))); err != nil {
+		return diag.Errorf("unable to parse ssh private key: %s", err)
+	}
+	return nil
+}
+
+func resourceSshServerSettingsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	config := SshServerConfiguration{}
+	if err := getConfiguration(ctx, m, &config); err != nil {
+		return diag.Errorf("failed to retrieve the ssh server settings during Read: %s", err)
+	}
+	return packSshServerSecurity(&SshServerSecurity{config.SshServer}, d)
+}
+
+func resourceSshServerSettingsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	unpacked := unpackSshServerSecurity(d)
+	content, err := yaml.Marshal(&unpacked)
+
+	if err != nil {
+		return diag.Errorf("failed to marshal ssh server settings during Update")
+	}
+
+	err = sendConfigurationPatch(ctx, content, m)
+	if err != nil {
+		return diag.Errorf("failed to send PATCH request to Artifactory during Update: %s", err)
+	}
+
+	// we should only have one ssh server settings resource, using same id
+	d.SetId("ssh_server_settings")
+	return resourceSshServerSettingsRead(ctx, d, m)
+}
+
+func resourceSshServerSettingsDelete(ctx context.Context, _ *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var content = `
+security:
+  sshServerSettings:
+    enableSshServer: false
+    sshServerPort: 1339
+    customUrlBase: ~
+`
+
+	err := sendConfigurationPatch(ctx, []byte(content), m)
+	if err != nil {
+		return diag.Errorf("failed to send PATCH request to Artifactory during Delete: %s", err)
+	}
+	return nil
+}
+
+// unpackSshServerSecurity only sends the key pair when the private key changed, like the crowd password
+func unpackSshServerSecurity(s *schema.ResourceData) *SshServerSecurity {
+	d := &ResourceData{s}
+	security := new(SshServerSecurity)
+
+	security.SshServer.Settings = SshServerSettings{
+		EnableSshServer: d.getBool("enable", false),
+		SshServerPort:   d.getInt("port", false),
+		CustomUrlBase:   d.getString("custom_url_base", false),
+		PrivateKey:      strings.ReplaceAll(d.getString("private_key", true), "\t", ""),
+	}
+	if security.SshServer.Settings.PrivateKey != "" || s.HasChange("public_key") {
+		security.SshServer.Settings.PublicKey = strings.TrimSpace(d.getString("public_key", false))
+	}
+	return security
+}
+
+// packSshServerSecurity reads back the public key, the private key is only in the configuration encrypted
+func packSshServerSecurity(s *SshServerSecurity, d *schema.ResourceData) diag.Diagnostics {
+	setValue := mkLens(d)
+	settings := s.SshServer.Settings
+
+	setValue("enable", settings.EnableSshServer)
+	setValue("port", settings.SshServerPort)
+	setValue("custom_url_base", settings.CustomUrlBase)
+	errors := setValue("public_key", settings.PublicKey)
+
+	if errors != nil && len(errors) > 0 {
+		return diag.Errorf("failed to pack ssh server settings %q", errors)
+	}
+	return nil
+}
@@ -1,30 +1,34 @@
 package artifactory
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
-	"net/http"
 	"os"
 	"strconv"
+	"time"
 
-	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/jfrog/jfrog-client-go/artifactory/services"
 )
 
+const usersEndpoint = "artifactory/api/security/users/"
+
 func resourceArtifactoryUser() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceUserCreate,
-		Read:   resourceUserRead,
-		Update: resourceUserUpdate,
-		Delete: resourceUserDelete,
-		Exists: resourceUserExists,
+	return upgradeFromVersion0(&schema.Resource{
+		CreateContext: resourceUserCreate,
+		ReadContext:   resourceUserRead,
+		UpdateContext: resourceUserUpdate,
+		DeleteContext: resourceUserDelete,
+		Timeouts:      defaultResourceTimeouts(),
+		CustomizeDiff: planUserPassword,
 
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -66,9 +70,10 @@ func resourceArtifactoryUser() *schema.Resource {
 				Optional: true,
 			},
 			"password": {
-				Type:      schema.TypeString,
-				Sensitive: true,
-				Optional:  true,
+				Type:          schema.TypeString,
+				Sensitive:     true,
+				Optional:      true,
+				ConflictsWith: []string{"generate_password"},
 				ValidateFunc: func(tfValue interface{}, key string) ([]string, []error) {
 					validationOn, _ := strconv.ParseBool(os.Getenv("JFROG_PASSWD_VALIDATION_ON"))
 					if validationOn {
@@ -80,30 +85,54 @@ func resourceArtifactoryUser() *schema.Resource {
 					}
 					return nil, nil
 				},
-				StateFunc: func(str interface{}) string {
-					// Avoid storing the actual value in the state and instead store the hash of it
-					value, ok := str.(string)
-					if !ok {
-						panic(fmt.Errorf("'str' is not a string %s", str))
-					}
-					hash := sha256.Sum256([]byte(value))
-					return base64.StdEncoding.EncodeToString(hash[:])
-				},
+				StateFunc: hashUserPassword,
+			},
+			"generate_password": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Let the provider generate the password, following password_policy. It's exported as generated_password",
+			},
+			"password_policy": passwordPolicySchema,
+			"rotation_days": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Generate a new password once the current one is this many days old. Never if not set",
+			},
+			"generated_password": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"password_generated_at": {
+				Type:     schema.TypeString,
+				Computed: true,
 			},
 		},
-	}
+	}, upgradeUserState)
 }
 
-func resourceUserExists(data *schema.ResourceData, m interface{}) (bool, error) {
-
-	d := &ResourceData{data}
-	name := d.Id()
-	return userExists(m.(*resty.Client), name)
+// hashUserPassword avoids storing the actual value in the state and instead stores the hash of it
+func hashUserPassword(str interface{}) string {
+	value, ok := str.(string)
+	if !ok {
+		panic(fmt.Errorf("'str' is not a string %s", str))
+	}
+	hash := sha256.Sum256([]byte(value))
+	return base64.StdEncoding.EncodeToString(hash[:])
 }
 
-func userExists(client *resty.Client, userName string) (bool, error) {
-	_, err := client.R().Head("artifactory/api/security/users/" + userName)
-	return err == nil, err
+// upgradeUserState hashes passwords that went into state as plain text, from before the StateFunc existed
+func upgradeUserState(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	password, ok := rawState["password"].(string)
+	if !ok || password == "" {
+		return rawState, nil
+	}
+	if hash, err := base64.StdEncoding.DecodeString(password); err == nil && len(hash) == sha256.Size {
+		return rawState, nil
+	}
+	rawState["password"] = hashUserPassword(password)
+	return rawState, nil
 }
 
 func unpackUser(s *schema.ResourceData) services.User {
@@ -142,73 +171,97 @@ func packUser(user services.User, d *schema.ResourceData) error {
 	return nil
 }
 
-func resourceUserCreate(d *schema.ResourceData, m interface{}) error {
+func resourceUserCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	user := unpackUser(d)
 
 	if user.Name == "" {
-		return fmt.Errorf("user name cannot be empty")
+		return diag.Errorf("user name cannot be empty")
 	}
 
+	generated, err := nextGeneratedPassword(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if generated != "" {
+		user.Password = generated
+	}
 	if user.Password == "" {
-		return fmt.Errorf("no password supplied. Please use any of the terraform random password generators")
+		return diag.Errorf("no password supplied. Please use generate_password or any of the terraform random password generators")
 	}
-	_, err := m.(*resty.Client).R().SetBody(user).Put("artifactory/api/security/users/" + user.Name)
+	_, err = m.(*ProviderMetadata).Client.R().SetContext(ctx).SetBody(user).Put(usersEndpoint + user.Name)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(user.Name)
-	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+	if generated != "" {
+		if err := saveGeneratedPassword(d, generated, time.Now()); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return diag.FromErr(resource.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
 		result := &services.User{}
-		resp, e := m.(*resty.Client).R().SetResult(result).Get("artifactory/api/security/users/" + user.Name)
+		_, e := m.(*ProviderMetadata).Client.R().SetContext(ctx).SetResult(result).Get(usersEndpoint + user.Name)
 
 		if e != nil {
-			if resp != nil && resp.StatusCode() == http.StatusNotFound {
+			if IsNotFound(e) {
 				return resource.RetryableError(fmt.Errorf("expected user to be created, but currently not found"))
 			}
-			return resource.NonRetryableError(fmt.Errorf("error describing user: %s", err))
+			return resource.NonRetryableError(fmt.Errorf("error describing user: %s", e))
 		}
 
 		return nil
-	})
+	}))
 }
 
-func resourceUserRead(rd *schema.ResourceData, m interface{}) error {
+func resourceUserRead(ctx context.Context, rd *schema.ResourceData, m interface{}) diag.Diagnostics {
 	d := &ResourceData{rd}
 
 	userName := d.Id()
 	user := &services.User{}
-	resp, err := m.(*resty.Client).R().SetResult(user).Get("artifactory/api/security/users/" + userName)
+	_, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).SetResult(user).Get(usersEndpoint + userName)
 
 	if err != nil {
-		if resp != nil && resp.StatusCode() == http.StatusNotFound {
+		if IsNotFound(err) {
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diag.FromErr(err)
 	}
-	return packUser(*user, rd)
+	return diag.FromErr(packUser(*user, rd))
 }
 
-func resourceUserUpdate(d *schema.ResourceData, m interface{}) error {
+func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	user := unpackUser(d)
-	_, err := m.(*resty.Client).R().SetBody(user).Post("artifactory/api/security/users/" + user.Name)
+	generated, err := nextGeneratedPassword(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if generated != "" {
+		user.Password = generated
+	}
+	_, err = m.(*ProviderMetadata).Client.R().SetContext(ctx).SetBody(user).Post(usersEndpoint + user.Name)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(user.Name)
-	return resourceUserRead(d, m)
+	if generated != "" {
+		if err := saveGeneratedPassword(d, generated, time.Now()); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return resourceUserRead(ctx, d, m)
 }
 
-func resourceUserDelete(rd *schema.ResourceData, m interface{}) error {
+func resourceUserDelete(ctx context.Context, rd *schema.ResourceData, m interface{}) diag.Diagnostics {
 	d := &ResourceData{rd}
 	userName := d.getString("name", false)
 
-	_, err := m.(*resty.Client).R().Delete("artifactory/api/security/users/" + userName)
-	if err != nil {
-		return fmt.Errorf("user %s not deleted. %s", userName, err)
+	_, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).Delete(usersEndpoint + userName)
+	if err != nil && !IsNotFound(err) {
+		return diag.Errorf("user %s not deleted. %s", userName, err)
 	}
 	return nil
 }
@@ -0,0 +1,87 @@
+package artifactory
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+)
+
+// resourceArtifactoryUserGroups is artifactory_group_members the other way round: it adds one user to groups, leaving
+// the user's other groups alone
+func resourceArtifactoryUserGroups() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceUserGroupsCreate,
+		ReadContext:   resourceUserGroupsRead,
+		UpdateContext: resourceUserGroupsUpdate,
+		DeleteContext: resourceUserGroupsDelete,
+		Timeouts:      defaultResourceTimeouts(),
+
+		Schema: map[string]*schema.Schema{
+			"username": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"groups": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+		},
+	}
+}
+
+func resourceUserGroupsCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	name := d.Get("username").(string)
+	if err := changeUserGroups(ctx, m, name, castToStringArr(d.Get("groups").(*schema.Set).List()), nil); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(name)
+	return resourceUserGroupsRead(ctx, d, m)
+}
+
+func resourceUserGroupsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	user := services.User{}
+	if _, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).SetResult(&user).Get(usersEndpoint + d.Id()); err != nil {
+		if IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	owned := d.Get("groups").(*schema.Set)
+	groups := schema.NewSet(schema.HashString, nil)
+	for _, group := range user.Groups {
+		if owned.Contains(group) {
+			groups.Add(group)
+		}
+	}
+
+	setValue := mkLens(d)
+	setValue("username", d.Id())
+	errors := setValue("groups", groups)
+	if errors != nil && len(errors) > 0 {
+		return diag.Errorf("failed saving state for user groups %q", errors)
+	}
+	return nil
+}
+
+func resourceUserGroupsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	old, new := d.GetChange("groups")
+	add := castToStringArr(new.(*schema.Set).Difference(old.(*schema.Set)).List())
+	remove := castToStringArr(old.(*schema.Set).Difference(new.(*schema.Set)).List())
+	if err := changeUserGroups(ctx, m, d.Id(), add, remove); err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceUserGroupsRead(ctx, d, m)
+}
+
+func resourceUserGroupsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return diag.FromErr(changeUserGroups(ctx, m, d.Id(), nil, castToStringArr(d.Get("groups").(*schema.Set).List())))
+}
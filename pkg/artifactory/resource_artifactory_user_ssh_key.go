@@ -0,0 +1,118 @@
+package artifactory
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"golang.org/x/crypto/ssh"
+)
+
+// UserSshKey is the part of a user with its ssh public key. It's posted on its own, so the rest of the user, which
+// artifactory_user manages, is left alone
+type UserSshKey struct {
+	SshPublicKey string `json:"sshPublicKey"`
+}
+
+func resourceArtifactoryUserSshKey() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceUserSshKeyUpdate,
+		ReadContext:   resourceUserSshKeyRead,
+		UpdateContext: resourceUserSshKeyUpdate,
+		DeleteContext: resourceUserSshKeyDelete,
+		Timeouts:      defaultResourceTimeouts(),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				return []*schema.ResourceData{d}, d.Set("user", d.Id())
+			},
+		},
+		Description: "The ssh public key Git LFS and the JFrog CLI authenticate the user with, against the ssh server of " +
+			"artifactory_ssh_server_settings",
+
+		Schema: map[string]*schema.Schema{
+			"user": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"public_key": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateSshPublicKey,
+				DiffSuppressFunc: sameSshPublicKey,
+				Description:      "OpenSSH public key, as in authorized_keys, eg ssh-ed25519 AAAA... user@host",
+			},
+		},
+	}
+}
+
+// validateSshPublicKey checks the key is a single line of authorized_keys, what ssh-keygen writes to id_*.pub
+func validateSshPublicKey(value interface{}, _ cty.Path) diag.Diagnostics {
+	key := strings.TrimSpace(value.(string))
+	if strings.Contains(key, "\n") {
+		return diag.Errorf("ssh public key must be a single line")
+	}
+	if strings.Contains(key, "BEGIN") {
+		return diag.Errorf("ssh public key must be in OpenSSH format, not PEM. 'ssh-keygen -i -f <file>' converts it")
+	}
+	if _, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key)); err != nil {
+		return diag.Errorf("unable to parse ssh public key: %s", err)
+	}
+	return nil
+}
+
+// sameSshPublicKey ignores the whitespace around the key, e.g. the trailing newline of file("id_ed25519.pub")
+func sameSshPublicKey(_, old, new string, _ *schema.ResourceData) bool {
+	return strings.TrimSpace(old) == strings.TrimSpace(new)
+}
+
+func resourceUserSshKeyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	key := UserSshKey{}
+	_, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).SetResult(&key).Get(usersEndpoint + d.Id())
+	if err != nil {
+		if IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	if key.SshPublicKey == "" {
+		d.SetId("")
+		return nil
+	}
+
+	setValue := mkLens(d)
+	setValue("user", d.Id())
+	errors := setValue("public_key", key.SshPublicKey)
+
+	if errors != nil && len(errors) > 0 {
+		return diag.Errorf("failed to pack user ssh key %q", errors)
+	}
+	return nil
+}
+
+func resourceUserSshKeyUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	user := d.Get("user").(string)
+	key := UserSshKey{SshPublicKey: strings.TrimSpace(d.Get("public_key").(string))}
+
+	_, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).SetBody(key).Post(usersEndpoint + user)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(user)
+	return resourceUserSshKeyRead(ctx, d, m)
+}
+
+func resourceUserSshKeyDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	_, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).SetBody(UserSshKey{}).Post(usersEndpoint + d.Id())
+	if err != nil && !IsNotFound(err) {
+		return diag.FromErr(err)
+	}
+	return nil
+}
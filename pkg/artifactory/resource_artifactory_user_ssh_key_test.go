@@ -0,0 +1,110 @@
+package artifactory
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/stretchr/testify/assert"
+)
+
+const userSshPublicKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIIieag2WozF2fT/DP/nkxabkxA1DsBXW/RggwaEaSxyW user@host"
+
+func TestAccUserSshKey_full(t *testing.T) {
+	_, fqrn, name := mkNames("ssh-key", "artifactory_user_ssh_key")
+	temp := `
+		resource "artifactory_user" "{{ .name }}" {
+			name     = "{{ .name }}"
+			password = "Passw0rd!"
+			email    = "{{ .name }}@example.com"
+		}
+
+		resource "artifactory_user_ssh_key" "{{ .name }}" {
+			user       = artifactory_user.{{ .name }}.name
+			public_key = "{{ .publicKey }}"
+		}
+	`
+	config := executeTemplate(name, temp, map[string]string{"name": name, "publicKey": userSshPublicKey})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		CheckDestroy:      testAccCheckUserDestroy("artifactory_user." + name),
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fqrn, "user", name),
+					resource.TestCheckResourceAttr(fqrn, "public_key", userSshPublicKey),
+				),
+			},
+			{
+				ResourceName:      fqrn,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestUserSshKeyLeavesUserAlone(t *testing.T) {
+	meta, client := newFakeMeta(t)
+	ctx := context.Background()
+
+	user := services.User{Name: "alice", Email: "alice@example.com", Password: "Passw0rd!", Admin: true}
+	if _, err := client.R().SetBody(user).Put(usersEndpoint + "alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	res := resourceArtifactoryUserSshKey()
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{
+		"user":       "alice",
+		"public_key": userSshPublicKey + "\n",
+	})
+	if diags := res.CreateContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("create failed: %v", diags)
+	}
+	assert.Equal(t, "alice", d.Id())
+	assert.Equal(t, userSshPublicKey, d.Get("public_key"), "the trailing newline of a .pub file isn't sent")
+
+	got := services.User{}
+	if _, err := client.R().SetResult(&got).Get(usersEndpoint + "alice"); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "alice@example.com", got.Email)
+	assert.True(t, got.Admin)
+
+	if diags := res.DeleteContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("delete failed: %v", diags)
+	}
+	if diags := res.ReadContext(ctx, d, meta); diags.HasError() || d.Id() != "" {
+		t.Errorf("expected a deleted key to clear the id, got %q %v", d.Id(), diags)
+	}
+	if _, err := client.R().SetResult(&got).Get(usersEndpoint + "alice"); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "alice@example.com", got.Email, "deleting the key keeps the user")
+}
+
+func TestValidateSshPublicKey(t *testing.T) {
+	for _, key := range []string{
+		userSshPublicKey,
+		"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIIieag2WozF2fT/DP/nkxabkxA1DsBXW/RggwaEaSxyW",
+		userSshPublicKey + "\n",
+	} {
+		assert.False(t, validateSshPublicKey(key, cty.Path{}).HasError(), key)
+	}
+	for _, key := range []string{
+		"",
+		"ssh-ed25519 not-base64",
+		"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIIieag2WozF2fT",
+		userSshPublicKey + "\n" + userSshPublicKey,
+		"-----BEGIN PUBLIC KEY-----\nMCowBQYDK2VwAyEAiJ5qDZajMXZ9P8M/+eTFpuTEDUOwFdb9GCDBoRpLHJY=\n-----END PUBLIC KEY-----",
+	} {
+		assert.True(t, validateSshPublicKey(key, cty.Path{}).HasError(), fmt.Sprintf("%q", key))
+	}
+}
@@ -1,13 +1,15 @@
 package artifactory
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
-
-	"github.com/go-resty/resty/v2"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -47,6 +49,103 @@ func TestAccUser_basic(t *testing.T) {
 	})
 }
 
+func TestAccUser_generatedPassword(t *testing.T) {
+	const userGenerated = `
+		resource "artifactory_user" "%s" {
+			name              = "the.dude%d"
+			email             = "the.dude%d@domain.com"
+			generate_password = true
+			rotation_days     = 30
+
+			password_policy {
+				length      = 32
+				min_special = 2
+			}
+		}
+	`
+	id := randomInt()
+	name := fmt.Sprintf("foobar-%d", id)
+	fqrn := fmt.Sprintf("artifactory_user.%s", name)
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		CheckDestroy:      testAccCheckUserDestroy(fqrn),
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(userGenerated, name, id, id),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(fqrn, "generated_password"),
+					resource.TestCheckResourceAttrSet(fqrn, "password_generated_at"),
+				),
+			},
+		},
+	})
+}
+
+func TestGeneratePassword(t *testing.T) {
+	policy := passwordPolicy{Length: 20, MinLower: 2, MinUpper: 3, MinDigits: 4, MinSpecial: 5}
+	password, err := generatePassword(policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(password) != 20 {
+		t.Errorf("expected 20 characters, got %q", password)
+	}
+	if _, errs := defaultPassValidation(password, "password"); len(errs) > 0 {
+		t.Errorf("generated password fails the default validation: %v", errs)
+	}
+	count := func(chars string) int {
+		n := 0
+		for _, c := range password {
+			if strings.ContainsRune(chars, c) {
+				n++
+			}
+		}
+		return n
+	}
+	if count(specialChars) < 5 || count(digitChars) < 4 || count(upperChars) < 3 {
+		t.Errorf("%q doesn't meet %+v", password, policy)
+	}
+
+	if _, err := generatePassword(passwordPolicy{Length: 8, MinLower: 4, MinUpper: 4, MinDigits: 1}); err == nil {
+		t.Error("expected minimums longer than the length to be rejected")
+	}
+}
+
+func TestUserPasswordRotationPlan(t *testing.T) {
+	res := resourceArtifactoryUser()
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":              "bob",
+		"email":             "bob@example.com",
+		"generate_password": true,
+		"rotation_days":     30,
+	})
+	plan := func(generatedAt time.Time) *terraform.InstanceDiff {
+		state := &terraform.InstanceState{ID: "bob", Attributes: map[string]string{
+			"id":                    "bob",
+			"name":                  "bob",
+			"email":                 "bob@example.com",
+			"generate_password":     "true",
+			"rotation_days":         "30",
+			"generated_password":    "Secret123",
+			"password_generated_at": generatedAt.UTC().Format(time.RFC3339),
+		}}
+		diff, err := res.Diff(context.Background(), state, config, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return diff
+	}
+
+	if diff := plan(time.Now().Add(-24 * time.Hour)); diff != nil && diff.Attributes["generated_password"] != nil {
+		t.Errorf("a fresh password shouldn't rotate, got %v", diff)
+	}
+	diff := plan(time.Now().Add(-31 * 24 * time.Hour))
+	if diff == nil || diff.Attributes["generated_password"] == nil || !diff.Attributes["generated_password"].NewComputed {
+		t.Errorf("expected a 31 days old password to rotate, got %v", diff)
+	}
+}
+
 func TestAccUser_full(t *testing.T) {
 	const userFull = `
 		resource "artifactory_user" "%s" {
@@ -87,7 +186,7 @@ func TestAccUser_full(t *testing.T) {
 func testAccCheckUserDestroy(id string) func(*terraform.State) error {
 	return func(s *terraform.State) error {
 		provider, _ := testAccProviders["artifactory"]()
-		client := provider.Meta().(*resty.Client)
+		client := provider.Meta().(*ProviderMetadata).Client
 
 		rs, ok := s.RootModule().Resources[id]
 
@@ -106,3 +205,51 @@ func testAccCheckUserDestroy(id string) func(*terraform.State) error {
 		return fmt.Errorf("error: User %s still exists", rs.Primary.ID)
 	}
 }
+
+// TestUserFixture replays a create/update/delete captured from the fake, so it pins what the provider sends, not how
+// a real artifactory answers
+func TestUserFixture(t *testing.T) {
+	meta := fixtureMeta(t, "user")
+	ctx := context.Background()
+
+	res := resourceArtifactoryUser()
+	d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{
+		"name":              "fixture.user",
+		"email":             "fixture.user@example.com",
+		"password":          "Password1",
+		"profile_updatable": true,
+	})
+	if diags := res.CreateContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("create failed: %v", diags)
+	}
+	if diags := res.ReadContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("read failed: %v", diags)
+	}
+	if d.Get("email") != "fixture.user@example.com" || d.Get("profile_updatable") != true || d.Get("admin") != false {
+		t.Errorf("unexpected state after read: %v %v %v", d.Get("email"), d.Get("profile_updatable"), d.Get("admin"))
+	}
+	if diags := res.DeleteContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("delete failed: %v", diags)
+	}
+}
+
+func TestUpgradeUserState(t *testing.T) {
+	res := resourceArtifactoryUser()
+	state := upgradeState(t, res, `{
+		"id": "the.dude",
+		"name": "the.dude",
+		"email": "the.dude@domain.com",
+		"password": "Password1",
+		"admin": false,
+		"groups": ["readers"]
+	}`)
+	if state["password"] != hashUserPassword("Password1") {
+		t.Errorf("expected the plain text password to be hashed, got %v", state["password"])
+	}
+
+	hashed := hashUserPassword("Password1")
+	state = upgradeState(t, res, fmt.Sprintf(`{"id": "the.dude", "name": "the.dude", "password": %q}`, hashed))
+	if state["password"] != hashed {
+		t.Errorf("an already hashed password was hashed again: %v", state["password"])
+	}
+}
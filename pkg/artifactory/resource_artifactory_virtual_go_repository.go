@@ -46,13 +46,14 @@ var goVirtReader = mkRepoRead(packGoVirtualRepository, newGoVirtStruct)
 
 func resourceArtifactoryGoVirtualRepository() *schema.Resource {
 	return &schema.Resource{
-		Create: mkRepoCreate(unpackGoVirtualRepository, goVirtReader),
-		Read:   goVirtReader,
-		Update: mkRepoUpdate(unpackGoVirtualRepository, goVirtReader),
-		Delete: deleteRepo,
-		Exists: repoExists,
+		CreateContext: mkRepoCreate(unpackGoVirtualRepository, goVirtReader),
+		ReadContext:   goVirtReader,
+		UpdateContext: mkRepoUpdate(unpackGoVirtualRepository, goVirtReader),
+		DeleteContext: deleteRepo,
+		Timeouts:      defaultResourceTimeouts(),
+		CustomizeDiff: defaultProjectKey,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 		Schema: goVirtualSchema,
 	}
@@ -53,13 +53,14 @@ var mvnVirtReader = mkRepoRead(packMavenVirtualRepository, func() interface{} {
 
 func resourceArtifactoryMavenVirtualRepository() *schema.Resource {
 	return &schema.Resource{
-		Create: mkRepoCreate(unpackMavenVirtualRepository, mvnVirtReader),
-		Read:   mvnVirtReader,
-		Update: mkRepoUpdate(unpackMavenVirtualRepository, mvnVirtReader),
-		Delete: deleteRepo,
-		Exists: repoExists,
+		CreateContext: mkRepoCreate(unpackMavenVirtualRepository, mvnVirtReader),
+		ReadContext:   mvnVirtReader,
+		UpdateContext: mkRepoUpdate(unpackMavenVirtualRepository, mvnVirtReader),
+		DeleteContext: deleteRepo,
+		Timeouts:      defaultResourceTimeouts(),
+		CustomizeDiff: defaultProjectKey,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 		Schema: mavenVirtualSchema,
 	}
@@ -7,6 +7,9 @@ import (
 )
 
 var legacySchema = map[string]*schema.Schema{
+	"project_key":              repoProjectKeySchema,
+	"skip_default_project_key": skipDefaultProjectKeySchema,
+	"project_environments":     repoProjectEnvironmentsSchema,
 	"key": {
 		Type:     schema.TypeString,
 		Required: true,
@@ -79,13 +82,14 @@ var readFunc = mkRepoRead(packVirtualRepository, func() interface{} {
 
 func resourceArtifactoryVirtualRepository() *schema.Resource {
 	return &schema.Resource{
-		Create: mkRepoCreate(unpackVirtualRepository, readFunc),
-		Read:   readFunc,
-		Update: mkRepoUpdate(unpackVirtualRepository, readFunc),
-		Delete: deleteRepo,
-		Exists: repoExists,
+		CreateContext: mkRepoCreate(unpackVirtualRepository, readFunc),
+		ReadContext:   readFunc,
+		UpdateContext: mkRepoUpdate(unpackVirtualRepository, readFunc),
+		DeleteContext: deleteRepo,
+		Timeouts:      defaultResourceTimeouts(),
+		CustomizeDiff: defaultProjectKey,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 		Schema: legacySchema,
 		DeprecationMessage: "This resource is deprecated and you should use repo type specific resources " +
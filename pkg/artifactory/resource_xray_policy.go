@@ -1,11 +1,13 @@
 package artifactory
 
 import (
+	"context"
 	"fmt"
 	"github.com/go-resty/resty/v2"
 	"log"
-	"net/http"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -59,13 +61,38 @@ type Policy struct {
 	Modified    *string       `json:"modified,omitempty"`
 }
 
+// xrayProjectKeySchema scopes a policy or watch to a project. Moving one between projects means recreating it
+var xrayProjectKeySchema = &schema.Schema{
+	Type:         schema.TypeString,
+	Optional:     true,
+	Computed:     true,
+	ForceNew:     true,
+	ValidateFunc: validateProjectKey,
+	Description:  "Project the policy or watch belongs to. Defaults to the provider's project_key",
+}
+
+// importXrayProjectScoped takes project_key:name for a policy or watch in a project, and a plain name for one
+// outside of any. Otherwise the first read wouldn't find it and drop it from the state
+func importXrayProjectScoped(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), ":", 2)
+	if len(parts) == 2 && projectKeyRegex.MatchString(parts[0]) {
+		d.SetId(parts[1])
+		if err := d.Set("project_key", parts[0]); err != nil {
+			return nil, err
+		}
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceXrayPolicy() *schema.Resource {
 	return &schema.Resource{
 		SchemaVersion: 1,
-		Create:        resourceXrayPolicyCreate,
-		Read:          resourceXrayPolicyRead,
-		Update:        resourceXrayPolicyUpdate,
-		Delete:        resourceXrayPolicyDelete,
+		CreateContext: resourceXrayPolicyCreate,
+		ReadContext:   resourceXrayPolicyRead,
+		UpdateContext: resourceXrayPolicyUpdate,
+		DeleteContext: resourceXrayPolicyDelete,
+		Timeouts:      defaultResourceTimeouts(),
+		CustomizeDiff: defaultProjectKey,
 		DeprecationMessage: "This portion of the provider uses V1 apis and will eventually be moved " +
 			"to the separate repo. The discussion is here: https://github.com/jfrog/terraform-provider-artifactory/issues/160, " +
 			"we encourage you to give input on new HCL and features",
@@ -73,7 +100,7 @@ func resourceXrayPolicy() *schema.Resource {
 			"It's only compatible with Bearer token auth method (Identity and Access => Access Tokens",
 
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: importXrayProjectScoped,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -82,6 +109,8 @@ func resourceXrayPolicy() *schema.Resource {
 				Required: true,
 				ForceNew: true,
 			},
+			"project_key":              xrayProjectKeySchema,
+			"skip_default_project_key": skipDefaultProjectKeySchema,
 			"type": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -483,79 +512,92 @@ func flattenBlockDownload(bd *BlockDownloadSettings) []interface{} {
 	return []interface{}{m}
 }
 
-func resourceXrayPolicyCreate(d *schema.ResourceData, m interface{}) error {
+func resourceXrayPolicyCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 
 	policy, err := expandPolicy(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	_, err = m.(*resty.Client).R().SetBody(policy).Post("xray/api/v1/policies")
+	_, err = xrayRequest(ctx, d, m).SetBody(policy).Post("xray/api/v1/policies")
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(*policy.Name)
-	return resourceXrayPolicyRead(d, m)
+	return resourceXrayPolicyRead(ctx, d, m)
 }
 
-func getPolicy(id string, client *resty.Client) (Policy, *resty.Response, error) {
+// xrayRequest starts a request for an xray policy or watch, scoped to its project when it has one. That's only ever
+// the project_key in the plan or state, see defaultProjectKey
+func xrayRequest(ctx context.Context, d *schema.ResourceData, m interface{}) *resty.Request {
+	request := m.(*ProviderMetadata).Client.R().SetContext(ctx)
+	if projectKey := d.Get("project_key").(string); projectKey != "" {
+		request.SetQueryParam("projectKey", projectKey)
+	}
+	return request
+}
+
+func getPolicy(request *resty.Request, id string) (Policy, *resty.Response, error) {
 	policy := Policy{}
-	resp, err := client.R().SetResult(&policy).Get("xray/api/v1/policies/" + id)
+	resp, err := request.SetResult(&policy).Get("xray/api/v1/policies/" + id)
 	return policy, resp, err
 }
-func resourceXrayPolicyRead(d *schema.ResourceData, m interface{}) error {
-	policy, resp, err := getPolicy(d.Id(), m.(*resty.Client))
+func resourceXrayPolicyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	policy, _, err := getPolicy(xrayRequest(ctx, d, m), d.Id())
 	if err != nil {
-		if resp != nil && resp.StatusCode() == http.StatusNotFound {
+		if IsNotFound(err) {
 			log.Printf("[WARN] Xray policy (%s) not found, removing from state", d.Id())
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diag.FromErr(err)
 	}
 
 	if err := d.Set("name", *policy.Name); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("type", *policy.Type); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if policy.Description != nil {
 		if err := d.Set("description", *policy.Description); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 	if err := d.Set("author", *policy.Author); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("created", *policy.Created); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("modified", *policy.Modified); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("rules", flattenRules(*policy.Rules)); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	return nil
 }
 
-func resourceXrayPolicyUpdate(d *schema.ResourceData, m interface{}) error {
+func resourceXrayPolicyUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 
 	policy, err := expandPolicy(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	_, err = m.(*resty.Client).R().SetBody(policy).Put("xray/api/v1/policies/" + d.Id())
+	_, err = xrayRequest(ctx, d, m).SetBody(policy).Put("xray/api/v1/policies/" + d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(*policy.Name)
-	return resourceXrayPolicyRead(d, m)
+	return resourceXrayPolicyRead(ctx, d, m)
 }
 
-func resourceXrayPolicyDelete(d *schema.ResourceData, m interface{}) error {
-	_, err := m.(*resty.Client).R().Delete("xray/api/v1/policies/" + d.Id())
-	return err
+func resourceXrayPolicyDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	_, err := xrayRequest(ctx, d, m).Delete("xray/api/v1/policies/" + d.Id())
+	if IsNotFound(err) {
+		return nil
+	}
+	return diag.FromErr(err)
 }
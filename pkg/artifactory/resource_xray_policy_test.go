@@ -1,14 +1,14 @@
 package artifactory
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"regexp"
 	"testing"
 
-	"github.com/go-resty/resty/v2"
-
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -252,7 +252,7 @@ func testAccCheckPolicyDestroy(s *terraform.State) error {
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type == "xray_policy" {
 			provider, _ := testAccProviders["artifactory"]()
-			policy, resp, err := getPolicy(rs.Primary.ID, provider.Meta().(*resty.Client))
+			policy, resp, err := getPolicy(provider.Meta().(*ProviderMetadata).Client.R().SetContext(context.Background()), rs.Primary.ID)
 
 			if err != nil {
 				if resp != nil {
@@ -450,3 +450,46 @@ resource "artifactory_xray_policy" "test" {
 }
 `, name, description, ruleName, allowedLicense)
 }
+
+func TestXrayRequestProjectKey(t *testing.T) {
+	client, err := buildResty("http://localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a policy from before the provider had a project_key must still be read outside of any project
+	meta := &ProviderMetadata{Client: client, ProjectKey: "default"}
+	res := resourceXrayPolicy()
+	existing := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{"name": "existing"})
+	existing.SetId("existing")
+	if projectKey := xrayRequest(context.Background(), existing, meta).QueryParam.Get("projectKey"); projectKey != "" {
+		t.Errorf("expected no projectKey for a policy without one in state, got %q", projectKey)
+	}
+
+	scoped := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{"name": "scoped", "project_key": "myproj"})
+	if projectKey := xrayRequest(context.Background(), scoped, meta).QueryParam.Get("projectKey"); projectKey != "myproj" {
+		t.Errorf("expected the policy's own projectKey, got %q", projectKey)
+	}
+}
+
+func TestXrayImportProjectScoped(t *testing.T) {
+	res := resourceXrayPolicy()
+	d := res.Data(nil)
+	d.SetId("myproj:security-policy")
+	imported, err := res.Importer.StateContext(context.Background(), d, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported[0].Id() != "security-policy" || imported[0].Get("project_key") != "myproj" {
+		t.Errorf("expected security-policy in myproj, got %q in %q", imported[0].Id(), imported[0].Get("project_key"))
+	}
+
+	d = res.Data(nil)
+	d.SetId("security-policy")
+	imported, err = res.Importer.StateContext(context.Background(), d, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported[0].Id() != "security-policy" || imported[0].Get("project_key") != "" {
+		t.Errorf("expected security-policy outside of any project, got %q in %q", imported[0].Id(), imported[0].Get("project_key"))
+	}
+}
@@ -1,11 +1,10 @@
 package artifactory
 
 import (
+	"context"
 	"log"
-	"net/http"
-
-	"github.com/go-resty/resty/v2"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
@@ -62,15 +61,17 @@ type Watch struct {
 
 func resourceXrayWatch() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceXrayWatchCreate,
-		Read:   resourceXrayWatchRead,
-		Update: resourceXrayWatchUpdate,
-		Delete: resourceXrayWatchDelete,
+		CreateContext: resourceXrayWatchCreate,
+		ReadContext:   resourceXrayWatchRead,
+		UpdateContext: resourceXrayWatchUpdate,
+		DeleteContext: resourceXrayWatchDelete,
+		Timeouts:      defaultResourceTimeouts(),
+		CustomizeDiff: defaultProjectKey,
 		DeprecationMessage: "This portion of the provider uses V1 apis and will eventually be moved " +
 			"to the separate repo. The discussion is here: https://github.com/jfrog/terraform-provider-artifactory/issues/160, " +
 			"we encourage you to give input on new HCL and features",
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: importXrayProjectScoped,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -79,6 +80,8 @@ func resourceXrayWatch() *schema.Resource {
 				Required: true,
 				ForceNew: true,
 			},
+			"project_key":              xrayProjectKeySchema,
+			"skip_default_project_key": skipDefaultProjectKeySchema,
 			"description": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -307,59 +310,61 @@ func flattenAssignedPolicies(policies *[]WatchAssignedPolicy) []interface{} {
 	return l
 }
 
-func resourceXrayWatchCreate(d *schema.ResourceData, m interface{}) error {
+func resourceXrayWatchCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 
 	watch := expandWatch(d)
-	_, err := m.(*resty.Client).R().SetBody(&watch).Post("xray/api/v2/watches")
+	_, err := xrayRequest(ctx, d, m).SetBody(&watch).Post("xray/api/v2/watches")
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(*watch.GeneralData.Name) // ID may be returned according to the API docs, but not in go-xray
-	return resourceXrayWatchRead(d, m)
+	return resourceXrayWatchRead(ctx, d, m)
 }
 
-func resourceXrayWatchRead(d *schema.ResourceData, m interface{}) error {
+func resourceXrayWatchRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	watch := Watch{}
-	resp, err := m.(*resty.Client).R().SetResult(&watch).Get("xray/api/v2/watches/" + d.Id())
+	_, err := xrayRequest(ctx, d, m).SetResult(&watch).Get("xray/api/v2/watches/" + d.Id())
 	if err != nil {
-
-		if resp != nil && resp.StatusCode() == http.StatusNotFound {
+		if IsNotFound(err) {
 			log.Printf("[WARN] Xray watch (%s) not found, removing from state", d.Id())
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diag.FromErr(err)
 	}
 
 	if err := d.Set("description", watch.GeneralData.Description); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("active", watch.GeneralData.Active); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("resources", flattenProjectResources(watch.ProjectResources)); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("assigned_policies", flattenAssignedPolicies(watch.AssignedPolicies)); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceXrayWatchUpdate(d *schema.ResourceData, m interface{}) error {
+func resourceXrayWatchUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	watch := expandWatch(d)
-	_, err := m.(*resty.Client).R().SetBody(&watch).Put("xray/api/v2/watches/" + d.Id())
+	_, err := xrayRequest(ctx, d, m).SetBody(&watch).Put("xray/api/v2/watches/" + d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(*watch.GeneralData.Name)
-	return resourceXrayWatchRead(d, m)
+	return resourceXrayWatchRead(ctx, d, m)
 }
 
-func resourceXrayWatchDelete(d *schema.ResourceData, m interface{}) error {
-	_, err := m.(*resty.Client).R().Delete("xray/api/v2/watches/" + d.Id())
-	return err
+func resourceXrayWatchDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	_, err := xrayRequest(ctx, d, m).Delete("xray/api/v2/watches/" + d.Id())
+	if IsNotFound(err) {
+		return nil
+	}
+	return diag.FromErr(err)
 }
@@ -1,8 +1,8 @@
 package artifactory
 
 import (
+	"context"
 	"fmt"
-	"github.com/go-resty/resty/v2"
 	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -149,7 +149,7 @@ func testAccCheckWatchDoesntExist(resourceName string) resource.TestCheckFunc {
 func testAccCheckWatchDestroy(s *terraform.State) error {
 	provider, _ := testAccProviders["artifactory"]()
 
-	client := provider.Meta().(*resty.Client)
+	client := provider.Meta().(*ProviderMetadata).Client
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type == "xray_watch" {
@@ -166,7 +166,7 @@ func testAccCheckWatchDestroy(s *terraform.State) error {
 
 		}
 		if rs.Type == "xray_policy" {
-			policy, resp, err := getPolicy(rs.Primary.ID, client)
+			policy, resp, err := getPolicy(client.R().SetContext(context.Background()), rs.Primary.ID)
 
 			if err != nil {
 				if resp != nil && resp.StatusCode() == http.StatusInternalServerError &&
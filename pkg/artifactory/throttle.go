@@ -0,0 +1,152 @@
+package artifactory
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// throttledTransport sits under resty, so every attempt - retries included - takes a slot and waits its
+// turn, no matter how many resources terraform is working on at once
+type throttledTransport struct {
+	base    http.RoundTripper
+	slots   chan struct{}
+	limiter *rateLimiter
+}
+
+func (t *throttledTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.slots != nil {
+		select {
+		case t.slots <- struct{}{}:
+			defer func() { <-t.slots }()
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+	if t.limiter != nil {
+		if err := t.limiter.wait(req); err != nil {
+			return nil, err
+		}
+	}
+	return t.base.RoundTrip(req)
+}
+
+// rateLimiter hands out evenly spaced start times, one every interval
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func (l *rateLimiter) wait(req *http.Request) error {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	select {
+	case <-time.After(delay):
+		return nil
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
+}
+
+// addThrottleToResty caps the client at maxConcurrent requests in flight and requestsPerSecond requests started.
+// Zero means no limit for either
+func addThrottleToResty(client *resty.Client, maxConcurrent, requestsPerSecond int) *resty.Client {
+	if maxConcurrent <= 0 && requestsPerSecond <= 0 {
+		return client
+	}
+	base := client.GetClient().Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	transport := &throttledTransport{base: base}
+	if maxConcurrent > 0 {
+		transport.slots = make(chan struct{}, maxConcurrent)
+	}
+	if requestsPerSecond > 0 {
+		transport.limiter = &rateLimiter{interval: time.Second / time.Duration(requestsPerSecond)}
+	}
+	return client.SetTransport(transport)
+}
+
+// retryAfterMaxWait caps how long a Retry-After may hold a request back. Every other retry keeps resty's
+// default cap of backoffMaxWait
+const (
+	retryAfterMaxWait = time.Minute
+	backoffMaxWait    = 2 * time.Second
+)
+
+// retryOnTooManyRequests retries a 429 and, as resty does when no condition is set, a request that never got
+// a response. Any other error response is final
+func retryOnTooManyRequests(response *resty.Response, err error) bool {
+	if err != nil && (response == nil || response.RawResponse == nil) {
+		return true
+	}
+	return response != nil && response.StatusCode() == http.StatusTooManyRequests
+}
+
+// retryAfter honors the Retry-After header artifactory (SaaS in particular) sends with a 429. It may either be
+// a number of seconds or a date. resty caps whatever this returns at the client's max wait time, which has to
+// leave room for a Retry-After, so every other retry gets its backoff here rather than from resty
+func retryAfter(client *resty.Client, response *resty.Response) (time.Duration, error) {
+	if wait := retryAfterHeader(response); wait > 0 {
+		if wait > retryAfterMaxWait {
+			wait = retryAfterMaxWait
+		}
+		return wait, nil
+	}
+	attempt := 1
+	if response != nil && response.Request != nil && response.Request.Attempt > 1 {
+		attempt = response.Request.Attempt
+	}
+	return jitterBackoff(client.RetryWaitTime, backoffMaxWait, attempt), nil
+}
+
+func retryAfterHeader(response *resty.Response) time.Duration {
+	if response == nil || response.RawResponse == nil || response.StatusCode() != http.StatusTooManyRequests {
+		return 0
+	}
+	header := response.Header().Get("Retry-After")
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil && time.Until(date) > 0 {
+		return time.Until(date)
+	}
+	return 0
+}
+
+// jitterBackoff doubles min with every attempt up to max and picks a random wait in the upper half of that,
+// the same way resty does on its own
+func jitterBackoff(min, max time.Duration, attempt int) time.Duration {
+	wait := max
+	if attempt <= 31 && min<<(attempt-1) < max {
+		wait = min << (attempt - 1)
+	}
+	wait = wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+	if wait < min {
+		wait = min
+	}
+	if wait <= 0 {
+		// 0 would hand the backoff back to resty, capped at retryAfterMaxWait
+		wait = time.Millisecond
+	}
+	return wait
+}
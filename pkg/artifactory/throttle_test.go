@@ -0,0 +1,143 @@
+package artifactory
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+func TestTooManyRequestsHonorsRetryAfter(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := buildResty(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if _, err := client.R().Get("/artifactory/api/repositories"); err != nil {
+		t.Fatalf("expected the 429 to be retried, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("expected to wait for Retry-After, only waited %s", elapsed)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestThrottleCapsConcurrentRequests(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			seen := atomic.LoadInt32(&maxInFlight)
+			if current <= seen || atomic.CompareAndSwapInt32(&maxInFlight, seen, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	client, err := buildResty(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = addThrottleToResty(client, 2, 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = client.R().Get("/artifactory/api/repositories")
+		}()
+	}
+	wg.Wait()
+	if maxInFlight > 2 {
+		t.Errorf("expected at most 2 requests in flight, saw %d", maxInFlight)
+	}
+}
+
+type failingTransport struct {
+	calls int32
+}
+
+func (t *failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	atomic.AddInt32(&t.calls, 1)
+	return nil, errors.New("connection refused")
+}
+
+func TestTransportErrorsAreRetried(t *testing.T) {
+	client, err := buildResty("http://artifactory.invalid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := &failingTransport{}
+	client.SetTransport(transport).SetRetryCount(3).SetRetryWaitTime(time.Millisecond)
+
+	if _, err := client.R().Get("/artifactory/api/repositories"); err == nil {
+		t.Fatal("expected the transport error to come back")
+	}
+	if transport.calls != 4 {
+		t.Errorf("expected 4 attempts, got %d", transport.calls)
+	}
+}
+
+func TestErrorResponsesAreNotRetried(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client, err := buildResty(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.R().Get("/artifactory/api/repositories"); err == nil {
+		t.Fatal("expected the 400 to come back as an error")
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestRetryAfterCapsOnlyRetryAfter(t *testing.T) {
+	client, err := buildResty("http://artifactory.invalid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	request := client.R()
+	request.Attempt = 10
+
+	throttled := &resty.Response{Request: request, RawResponse: &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{"Retry-After": []string{"3600"}},
+	}}
+	if wait, _ := retryAfter(client, throttled); wait != retryAfterMaxWait {
+		t.Errorf("expected Retry-After to be capped at %s, got %s", retryAfterMaxWait, wait)
+	}
+
+	failed := &resty.Response{Request: request}
+	if wait, _ := retryAfter(client, failed); wait <= 0 || wait > backoffMaxWait {
+		t.Errorf("expected a backoff of at most %s, got %s", backoffMaxWait, wait)
+	}
+}
@@ -0,0 +1,60 @@
+package artifactory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var rotateBeforeSchema = &schema.Schema{
+	Type:     schema.TypeString,
+	Optional: true,
+	ValidateFunc: func(value interface{}, key string) ([]string, []error) {
+		if d, err := time.ParseDuration(value.(string)); err != nil || d <= 0 {
+			return nil, []error{fmt.Errorf("%s must be a positive duration, eg 72h, got %q", key, value)}
+		}
+		return nil, nil
+	},
+	Description: "Replace the token on the first plan that is within this duration of its expiry, eg 72h",
+}
+
+// tokenRotationDue tells whether a token expiring at expiry (RFC 3339) is within rotateBefore of it. Tokens that
+// don't expire never are
+func tokenRotationDue(expiry, rotateBefore string, now time.Time) bool {
+	if expiry == "" || rotateBefore == "" {
+		return false
+	}
+	end, err := time.Parse(time.RFC3339, expiry)
+	if err != nil {
+		return false
+	}
+	window, err := time.ParseDuration(rotateBefore)
+	if err != nil {
+		return false
+	}
+	return !now.Add(window).Before(end)
+}
+
+// planTokenRotation has the plan replace a token that's due for rotation. With refresh it's planned as an update
+// instead, for the resources whose Update trades the refresh token for a new token. The attributes in computed are
+// the ones that come out different either way
+func planTokenRotation(expiry func(*schema.ResourceDiff) string, refresh func(*schema.ResourceDiff) bool, computed ...string) schema.CustomizeDiffFunc {
+	return func(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+		if d.Id() == "" || !tokenRotationDue(expiry(d), d.Get("rotate_before").(string), time.Now()) {
+			return nil
+		}
+		// before SetNewComputed, after which the refresh token reads as unknown
+		refreshable := refresh != nil && refresh(d)
+		for _, key := range computed {
+			if err := d.SetNewComputed(key); err != nil {
+				return err
+			}
+		}
+		if refreshable {
+			return nil
+		}
+		return d.ForceNew("access_token")
+	}
+}
@@ -0,0 +1,192 @@
+package artifactory
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	lowerChars   = "abcdefghijklmnopqrstuvwxyz"
+	upperChars   = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digitChars   = "0123456789"
+	specialChars = "!#$%&*+-=?@^_"
+)
+
+// passwordPolicy is what a generated password has to satisfy. The minimums default to what defaultPassValidation
+// checks, so a generated password always passes it
+type passwordPolicy struct {
+	Length     int
+	MinLower   int
+	MinUpper   int
+	MinDigits  int
+	MinSpecial int
+}
+
+var defaultPasswordPolicy = passwordPolicy{Length: 24, MinLower: 1, MinUpper: 1, MinDigits: 1}
+
+var passwordPolicySchema = &schema.Schema{
+	Type:        schema.TypeList,
+	Optional:    true,
+	MaxItems:    1,
+	Description: "Rules for the generated password, for organizations whose rules are stricter than the defaults",
+	Elem: &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"length": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultPasswordPolicy.Length,
+				ValidateFunc: validation.IntBetween(8, 128),
+			},
+			"min_lowercase": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultPasswordPolicy.MinLower,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"min_uppercase": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultPasswordPolicy.MinUpper,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"min_digits": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultPasswordPolicy.MinDigits,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"min_special": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultPasswordPolicy.MinSpecial,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+		},
+	},
+}
+
+func unpackPasswordPolicy(d *schema.ResourceData) passwordPolicy {
+	v, ok := d.GetOk("password_policy")
+	if !ok || len(v.([]interface{})) == 0 || v.([]interface{})[0] == nil {
+		return defaultPasswordPolicy
+	}
+	policy := v.([]interface{})[0].(map[string]interface{})
+	return passwordPolicy{
+		Length:     policy["length"].(int),
+		MinLower:   policy["min_lowercase"].(int),
+		MinUpper:   policy["min_uppercase"].(int),
+		MinDigits:  policy["min_digits"].(int),
+		MinSpecial: policy["min_special"].(int),
+	}
+}
+
+// generatePassword draws the minimum of each class, fills up to the length from all of them and shuffles the lot
+func generatePassword(policy passwordPolicy) (string, error) {
+	required := policy.MinLower + policy.MinUpper + policy.MinDigits + policy.MinSpecial
+	if required > policy.Length {
+		return "", fmt.Errorf("password_policy asks for %d characters of the minimums but a length of %d", required, policy.Length)
+	}
+	all := lowerChars + upperChars + digitChars
+	if policy.MinSpecial > 0 {
+		all += specialChars
+	}
+
+	var password []byte
+	for _, class := range []struct {
+		chars string
+		count int
+	}{
+		{lowerChars, policy.MinLower},
+		{upperChars, policy.MinUpper},
+		{digitChars, policy.MinDigits},
+		{specialChars, policy.MinSpecial},
+		{all, policy.Length - required},
+	} {
+		for i := 0; i < class.count; i++ {
+			c, err := randomIndex(len(class.chars))
+			if err != nil {
+				return "", err
+			}
+			password = append(password, class.chars[c])
+		}
+	}
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := randomIndex(i + 1)
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j] = password[j], password[i]
+	}
+	return string(password), nil
+}
+
+func randomIndex(n int) (int, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(i.Int64()), nil
+}
+
+// passwordDue tells whether a generated password has to be (re)generated: it never was, or it's older than
+// rotationDays. No rotationDays means it's kept until the policy changes
+func passwordDue(generatedAt string, rotationDays int, now time.Time) bool {
+	if generatedAt == "" {
+		return true
+	}
+	if rotationDays <= 0 {
+		return false
+	}
+	generated, err := time.Parse(time.RFC3339, generatedAt)
+	if err != nil {
+		return true
+	}
+	return !now.Before(generated.Add(time.Duration(rotationDays) * 24 * time.Hour))
+}
+
+// planUserPassword makes a plan show the generated password changing, so rotation happens on apply rather than by
+// surprise. Update regenerates exactly when password_generated_at is in the plan
+func planUserPassword(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	generatedAt := d.Get("password_generated_at").(string)
+	if !d.Get("generate_password").(bool) {
+		if generatedAt == "" {
+			return nil
+		}
+		if err := d.SetNew("generated_password", ""); err != nil {
+			return err
+		}
+		return d.SetNew("password_generated_at", "")
+	}
+	if !passwordDue(generatedAt, d.Get("rotation_days").(int), time.Now()) && !d.HasChange("password_policy") {
+		return nil
+	}
+	if err := d.SetNewComputed("generated_password"); err != nil {
+		return err
+	}
+	return d.SetNewComputed("password_generated_at")
+}
+
+// nextGeneratedPassword generates a password for the user when one is due, returning "" otherwise. The caller sends it
+// and then saves it with saveGeneratedPassword
+func nextGeneratedPassword(d *schema.ResourceData) (string, error) {
+	if !d.Get("generate_password").(bool) || (d.Id() != "" && !d.HasChange("password_generated_at")) {
+		return "", nil
+	}
+	return generatePassword(unpackPasswordPolicy(d))
+}
+
+func saveGeneratedPassword(d *schema.ResourceData, password string, now time.Time) error {
+	setValue := mkLens(d)
+	setValue("generated_password", password)
+	errors := setValue("password_generated_at", now.UTC().Format(time.RFC3339))
+	if errors != nil && len(errors) > 0 {
+		return fmt.Errorf("failed saving the generated password %q", errors)
+	}
+	return nil
+}
@@ -2,14 +2,17 @@ package artifactory
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/xml"
 	"fmt"
 	"math/rand"
+	"strings"
+	"sync"
 	"text/template"
 	"time"
 
-	"github.com/go-resty/resty/v2"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -114,6 +117,47 @@ func getMD5Hash(o interface{}) string {
 	return hex.EncodeToString(hasher.Sum(nil))
 }
 
+// secretFingerprint is the getMD5Hash of a secret read back from artifactory, to compare with the one in state. There
+// is none for a secret artifactory only hands back encrypted, AM.<ciphertext> with its master key, or masked
+func secretFingerprint(secret string) (string, bool) {
+	if secret == "" || strings.HasPrefix(secret, "AM.") || strings.Trim(secret, "*") == "" {
+		return "", false
+	}
+	return getMD5Hash(secret), true
+}
+
+// isMD5Hash tells a value getMD5Hash produced from a plain one. A plain text password that happens to be 64 hex
+// characters is taken for a hash, which is the only way to tell them apart
+func isMD5Hash(value string) bool {
+	if len(value) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(value)
+	return err == nil
+}
+
+// hashPasswordInState replaces a plain text password left in old state with the hash the StateFunc stores today
+func hashPasswordInState(state map[string]interface{}) {
+	if password, ok := state["password"].(string); ok && !isMD5Hash(password) {
+		state["password"] = getMD5Hash(password)
+	}
+}
+
+// upgradeFromVersion0 bumps res to schema version 1 with upgrade handling version 0 state. Only for resources
+// whose version 0 state has the same shape as the current schema (only the meaning of some values changed),
+// as the current schema is what decodes it
+func upgradeFromVersion0(res *schema.Resource, upgrade schema.StateUpgradeFunc) *schema.Resource {
+	res.SchemaVersion = 1
+	res.StateUpgraders = []schema.StateUpgrader{
+		{
+			Version: 0,
+			Type:    res.CoreConfigSchema().ImpliedType(),
+			Upgrade: upgrade,
+		},
+	}
+	return res
+}
+
 var randomInt = func() func() int {
 	rand.Seed(time.Now().UnixNano())
 	return rand.Int
@@ -146,6 +190,44 @@ func mergeSchema(schemata ...map[string]*schema.Schema) map[string]*schema.Schem
 	return result
 }
 
+// computedSchema turns a resource schema into the one of the matching data source: every attribute is read back,
+// except key which is required to look it up. Attributes in omit (eg a password artifactory never returns) are dropped
+func computedSchema(skeema map[string]*schema.Schema, key string, omit ...string) map[string]*schema.Schema {
+	result := computedSchemaOf(skeema)
+	for _, name := range omit {
+		delete(result, name)
+	}
+	result[key] = &schema.Schema{
+		Type:         skeema[key].Type,
+		Required:     true,
+		ValidateFunc: skeema[key].ValidateFunc,
+	}
+	return result
+}
+
+func computedAttribute(s *schema.Schema) *schema.Schema {
+	computed := &schema.Schema{
+		Type:        s.Type,
+		Computed:    true,
+		Sensitive:   s.Sensitive,
+		Set:         s.Set,
+		Description: s.Description,
+		Elem:        s.Elem,
+	}
+	if elem, ok := s.Elem.(*schema.Resource); ok {
+		computed.Elem = &schema.Resource{Schema: computedSchemaOf(elem.Schema)}
+	}
+	return computed
+}
+
+func computedSchemaOf(skeema map[string]*schema.Schema) map[string]*schema.Schema {
+	result := map[string]*schema.Schema{}
+	for name, s := range skeema {
+		result[name] = computedAttribute(s)
+	}
+	return result
+}
+
 func executeTemplate(name, temp string, fields interface{}) string {
 	var tpl bytes.Buffer
 	if err := template.Must(template.New(name).Parse(temp)).Execute(&tpl, fields); err != nil {
@@ -173,15 +255,62 @@ func mkLens(d *schema.ResourceData) Lens {
 	}
 }
 
-func sendConfigurationPatch(content []byte, m interface{}) error {
+// ctxLock is a mutex that stops waiting when the context is done, so a cancelled or timed out apply isn't stuck behind
+// the lock. Its zero value is unlocked
+type ctxLock struct {
+	once      sync.Once
+	semaphore chan struct{}
+}
+
+func (l *ctxLock) lock(ctx context.Context) (func(), error) {
+	l.once.Do(func() { l.semaphore = make(chan struct{}, 1) })
+	select {
+	case l.semaphore <- struct{}{}:
+		return func() { <-l.semaphore }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// lockDescriptor serializes the calls that rewrite artifactory's global config descriptor (repositories, their
+// project, replications, and the system configuration patches). Artifactory merges each of these into the whole
+// descriptor, and two at once end in 'Could not merge and save new descriptor'. Call the returned func to unlock
+func lockDescriptor(ctx context.Context, m interface{}) (func(), error) {
+	return m.(*ProviderMetadata).descriptorLock.lock(ctx)
+}
 
-	_, err := m.(*resty.Client).R().SetBody(content).
+// lockMembership serializes the read-modify-write of a user's groups done by artifactory_group_members and
+// artifactory_user_groups. Two of them touching the same user at once would each drop the other's group
+func lockMembership(ctx context.Context, m interface{}) (func(), error) {
+	return m.(*ProviderMetadata).membershipLock.lock(ctx)
+}
+
+func sendConfigurationPatch(ctx context.Context, content []byte, m interface{}) error {
+	unlock, err := lockDescriptor(ctx, m)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	_, err = m.(*ProviderMetadata).Client.R().SetContext(ctx).SetBody(content).
 		SetHeader("Content-Type", "application/yaml").
 		Patch("artifactory/api/system/configuration")
 
 	return err
 }
 
+// getConfiguration reads the system configuration, artifactory.config.xml, into result. It's where settings that only
+// have a yaml patch to write them, and no endpoint of their own, can be read back from. Secrets in it are encrypted
+func getConfiguration(ctx context.Context, m interface{}, result interface{}) error {
+	resp, err := m.(*ProviderMetadata).Client.R().SetContext(ctx).
+		SetHeader("Accept", "application/xml").
+		Get("artifactory/api/system/configuration")
+	if err != nil {
+		return err
+	}
+	return xml.Unmarshal(resp.Body(), result)
+}
+
 func BoolPtr(v bool) *bool { return &v }
 
 func IntPtr(v int) *int { return &v }
@@ -189,3 +318,13 @@ func IntPtr(v int) *int { return &v }
 func Int64Ptr(v int64) *int64 { return &v }
 
 func StringPtr(v string) *string { return &v }
+
+// defaultResourceTimeouts bounds the context each CRUD call gets; users can override per resource with a timeouts block
+func defaultResourceTimeouts() *schema.ResourceTimeout {
+	return &schema.ResourceTimeout{
+		Create: schema.DefaultTimeout(5 * time.Minute),
+		Read:   schema.DefaultTimeout(5 * time.Minute),
+		Update: schema.DefaultTimeout(5 * time.Minute),
+		Delete: schema.DefaultTimeout(5 * time.Minute),
+	}
+}
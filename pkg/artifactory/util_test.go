@@ -1,14 +1,22 @@
 package artifactory
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/go-resty/resty/v2"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"math"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
 )
 
 func fmtMapToHcl(fields map[string]interface{}) string {
@@ -91,14 +99,11 @@ func verifyDeleted(id string, check CheckFun) func(*terraform.State) error {
 			return fmt.Errorf("error: Resource id [%s] not found", id)
 		}
 		provider, _ := testAccProviders["artifactory"]()
-		client := provider.Meta().(*resty.Client)
-		resp, err := check(rs.Primary.ID, client.R())
+		client := provider.Meta().(*ProviderMetadata).Client
+		_, err := check(rs.Primary.ID, client.R())
 		if err != nil {
-			if resp != nil {
-				switch resp.StatusCode() {
-				case http.StatusNotFound, http.StatusBadRequest:
-					return nil
-				}
+			if HasStatus(err, http.StatusNotFound, http.StatusBadRequest) {
+				return nil
 			}
 			return err
 		}
@@ -109,3 +114,75 @@ func verifyDeleted(id string, check CheckFun) func(*terraform.State) error {
 func testCheckRepo(id string, request *resty.Request) (*resty.Response, error) {
 	return checkRepo(id, request.AddRetryCondition(neverRetry))
 }
+
+func TestDescriptorWritesAreSerialized(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if current := atomic.AddInt32(&inFlight, 1); current > atomic.LoadInt32(&maxInFlight) {
+			atomic.StoreInt32(&maxInFlight, current)
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+	}))
+	defer server.Close()
+
+	client, err := buildResty(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta := &ProviderMetadata{Client: client}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = sendConfigurationPatch(context.Background(), []byte("security: {}"), meta)
+		}()
+	}
+	wg.Wait()
+	if maxInFlight != 1 {
+		t.Errorf("expected configuration patches to be sent one at a time, saw %d at once", maxInFlight)
+	}
+}
+
+func TestDescriptorLockGivesUpWithContext(t *testing.T) {
+	meta := &ProviderMetadata{}
+	unlock, err := lockDescriptor(context.Background(), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := lockDescriptor(ctx, meta); err != context.DeadlineExceeded {
+		t.Errorf("expected waiting on a held lock to end with the context, got %v", err)
+	}
+
+	unlock()
+	unlock, err = lockDescriptor(context.Background(), meta)
+	if err != nil {
+		t.Fatalf("expected the lock to be free once released, got %v", err)
+	}
+	unlock()
+}
+
+// upgradeState runs old state JSON through every upgrader of res, the way terraform does on the first plan after an
+// upgrade, and checks that what comes out decodes with the current schema
+func upgradeState(t *testing.T, res *schema.Resource, oldState string) map[string]interface{} {
+	t.Helper()
+	state := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(oldState), &state); err != nil {
+		t.Fatal(err)
+	}
+	for _, upgrader := range res.StateUpgraders {
+		var err error
+		if state, err = upgrader.Upgrade(context.Background(), state, nil); err != nil {
+			t.Fatalf("upgrading from version %d: %s", upgrader.Version, err)
+		}
+	}
+	if _, err := schema.JSONMapToStateValue(state, res.CoreConfigSchema()); err != nil {
+		t.Fatalf("upgraded state doesn't match the schema: %s", err)
+	}
+	return state
+}
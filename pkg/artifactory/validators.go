@@ -21,6 +21,16 @@ func validateLowerCase(value interface{}, key string) (ws []string, es []error)
 	return
 }
 
+var projectKeyRegex = regexp.MustCompile(`^[a-z][a-z0-9]{1,31}$`)
+
+// validateProjectKey checks a JFrog project key: 2 to 32 lowercase letters and digits, starting with a letter
+func validateProjectKey(value interface{}, key string) (ws []string, es []error) {
+	if !projectKeyRegex.MatchString(value.(string)) {
+		es = append(es, fmt.Errorf("%s must be 2 to 32 lowercase letters and digits, starting with a letter. Got %q", key, value))
+	}
+	return
+}
+
 func validateCron(value interface{}, key string) (ws []string, es []error) {
 	_, err := cronexpr.Parse(value.(string))
 	if err != nil {
@@ -0,0 +1,107 @@
+package fakeartifactory
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+func (s *Server) registerAccess() {
+	s.handle(http.MethodPost, "access/api/v1/tokens/", s.createAccessToken)
+	s.handle(http.MethodGet, "access/api/v1/tokens/", s.getAccessToken)
+	s.handle(http.MethodDelete, "access/api/v1/tokens/", s.revokeAccessToken)
+}
+
+// createAccessToken answers like access does: the secrets once, in the response, and only the token's metadata from
+// then on. A refresh_token grant revokes the refreshed token and issues one like it
+func (s *Server) createAccessToken(w http.ResponseWriter, r *http.Request, _ string) {
+	body, err := readObject(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if body["grant_type"] == "refresh_token" {
+		refreshToken, _ := body["refresh_token"].(string)
+		refreshed, ok := s.refreshTokens[refreshToken]
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "Invalid refresh token")
+			return
+		}
+		delete(s.refreshTokens, refreshToken)
+		delete(s.accessTokens, refreshed["token_id"].(string))
+		body = refreshed["request"].(object)
+	}
+	writeJSON(w, http.StatusOK, s.issueAccessToken(body))
+}
+
+func (s *Server) issueAccessToken(body object) object {
+	username, _ := body["username"].(string)
+	if username == "" {
+		username = "admin"
+	}
+	scope, _ := body["scope"].(string)
+	if scope == "" {
+		scope = "applied-permissions/user"
+	}
+	expiresIn, _ := body["expires_in"].(float64)
+	refreshable, _ := body["refreshable"].(bool)
+	issuedAt := time.Now().Unix()
+
+	id := randomString(32)
+	info := object{
+		"token_id":    id,
+		"subject":     fmt.Sprintf("jfac@fake/users/%s", username),
+		"issuer":      "jfac@fake",
+		"issued_at":   issuedAt,
+		"refreshable": refreshable,
+		"description": body["description"],
+	}
+	if expiresIn > 0 {
+		info["expiry"] = issuedAt + int64(expiresIn)
+	}
+	s.accessTokens[id] = info
+
+	token := object{
+		"token_id":     id,
+		"access_token": randomString(64),
+		"scope":        scope,
+		"token_type":   "Bearer",
+	}
+	if expiresIn > 0 {
+		token["expires_in"] = int64(expiresIn)
+	}
+	if refreshable {
+		token["refresh_token"] = randomString(64)
+		s.refreshTokens[token["refresh_token"].(string)] = object{"token_id": id, "request": body}
+	}
+	if include, _ := body["include_reference_token"].(bool); include {
+		token["reference_token"] = randomString(64)
+	}
+	return token
+}
+
+func (s *Server) getAccessToken(w http.ResponseWriter, r *http.Request, id string) {
+	if id == "" {
+		list := []object{}
+		for _, key := range sortedKeys(s.accessTokens) {
+			list = append(list, s.accessTokens[key])
+		}
+		writeJSON(w, http.StatusOK, object{"tokens": list})
+		return
+	}
+	info, ok := s.accessTokens[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Token not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, info)
+}
+
+func (s *Server) revokeAccessToken(w http.ResponseWriter, r *http.Request, id string) {
+	if _, ok := s.accessTokens[id]; !ok {
+		writeError(w, http.StatusNotFound, "Token not found")
+		return
+	}
+	delete(s.accessTokens, id)
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,198 @@
+package fakeartifactory
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+func (s *Server) registerConfiguration() {
+	s.handle(http.MethodPatch, "artifactory/api/system/configuration", s.patchConfiguration)
+	s.handle(http.MethodGet, "artifactory/api/system/configuration", s.getConfiguration)
+	s.handle(http.MethodGet, "artifactory/api/securityconfig", s.getSecurityConfig)
+	s.handle(http.MethodGet, "artifactory/api/oauth", s.getOauth)
+	s.handle(http.MethodGet, "artifactory/api/saml/config", s.getSaml)
+}
+
+// patchConfiguration applies a YAML patch to the descriptor the way artifactory does: maps are merged key by key,
+// anything else is replaced and a null removes the key
+func (s *Server) patchConfiguration(w http.ResponseWriter, r *http.Request, _ string) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var patch interface{}
+	if err := yaml.Unmarshal(body, &patch); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Could not parse yaml: %s", err))
+		return
+	}
+	patchMap, ok := normalize(patch).(map[string]interface{})
+	if !ok {
+		writeError(w, http.StatusBadRequest, "Configuration patch must be a map")
+		return
+	}
+	mergePatch(s.descriptor, patchMap)
+	_, _ = w.Write([]byte("[OK] Successfully merged configuration"))
+}
+
+func mergePatch(into, patch map[string]interface{}) {
+	for key, value := range patch {
+		if value == nil {
+			delete(into, key)
+			continue
+		}
+		patchChild, patchIsMap := value.(map[string]interface{})
+		existing, existingIsMap := into[key].(map[string]interface{})
+		if patchIsMap && existingIsMap {
+			mergePatch(existing, patchChild)
+			continue
+		}
+		if patchIsMap {
+			fresh := map[string]interface{}{}
+			mergePatch(fresh, patchChild)
+			into[key] = fresh
+			continue
+		}
+		into[key] = value
+	}
+}
+
+// normalize turns what yaml.v2 produces (map[interface{}]interface{}) into something encoding/json can write
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		result := map[string]interface{}{}
+		for key, item := range v {
+			result[fmt.Sprint(key)] = normalize(item)
+		}
+		return result
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalize(item)
+		}
+	}
+	return value
+}
+
+// namedCollections are the maps of the yaml that are lists in the xml: the element each entry becomes, and the
+// element its map key goes into
+var namedCollections = map[string][2]string{
+	"ldapSettings":          {"ldapSetting", "key"},
+	"ldapGroupSettings":     {"ldapGroupSetting", "name"},
+	"samlProvidersSettings": {"samlProviderSetting", "name"},
+}
+
+// getConfiguration renders the descriptor as artifactory.config.xml. Passwords come out encrypted, like artifactory
+// has them
+func (s *Server) getConfiguration(w http.ResponseWriter, r *http.Request, _ string) {
+	buf := &bytes.Buffer{}
+	buf.WriteString(xml.Header)
+	writeXML(buf, "config", map[string]interface{}(s.descriptor))
+	w.Header().Set("Content-Type", "application/xml")
+	_, _ = w.Write(buf.Bytes())
+}
+
+func writeXML(buf *bytes.Buffer, name string, value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		fmt.Fprintf(buf, "<%s>", name)
+		collection, named := namedCollections[name]
+		for _, key := range keys {
+			if !named {
+				writeXML(buf, key, v[key])
+				continue
+			}
+			entry := map[string]interface{}{collection[1]: key}
+			if fields, ok := v[key].(map[string]interface{}); ok {
+				for field, fieldValue := range fields {
+					entry[field] = fieldValue
+				}
+			}
+			writeXML(buf, collection[0], entry)
+		}
+		fmt.Fprintf(buf, "</%s>", name)
+	case []interface{}:
+		for _, item := range v {
+			writeXML(buf, name, item)
+		}
+	default:
+		text := fmt.Sprint(v)
+		if strings.HasSuffix(strings.ToLower(name), "password") {
+			text = "AM." + base64.StdEncoding.EncodeToString([]byte(text))
+		}
+		fmt.Fprintf(buf, "<%s>", name)
+		_ = xml.EscapeText(buf, []byte(text))
+		fmt.Fprintf(buf, "</%s>", name)
+	}
+}
+
+// descriptorSection walks down the descriptor, returning an empty map for anything that isn't there
+func (s *Server) descriptorSection(path ...string) map[string]interface{} {
+	section := map[string]interface{}(s.descriptor)
+	for _, key := range path {
+		child, ok := section[key].(map[string]interface{})
+		if !ok {
+			return map[string]interface{}{}
+		}
+		section = child
+	}
+	return section
+}
+
+func boolValue(section map[string]interface{}, key string) bool {
+	b, _ := section[key].(bool)
+	return b
+}
+
+func (s *Server) getSecurityConfig(w http.ResponseWriter, r *http.Request, _ string) {
+	security := s.descriptorSection("security")
+	writeJSON(w, http.StatusOK, object{
+		"anonAccessEnabled": boolValue(security, "anonAccessEnabled"),
+	})
+}
+
+// getOauth reshapes security.oauthSettings into what the UI endpoint returns: the providers map becomes a list
+func (s *Server) getOauth(w http.ResponseWriter, r *http.Request, _ string) {
+	settings := s.descriptorSection("security", "oauthSettings")
+	providersSettings, _ := settings["oauthProvidersSettings"].(map[string]interface{})
+
+	names := make([]string, 0, len(providersSettings))
+	for name := range providersSettings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	providers := []object{}
+	for _, name := range names {
+		provider, _ := providersSettings[name].(map[string]interface{})
+		entry := object{"name": name}
+		for k, v := range provider {
+			entry[k] = v
+		}
+		providers = append(providers, entry)
+	}
+
+	writeJSON(w, http.StatusOK, object{
+		"enabled":                  boolValue(settings, "enableIntegration"),
+		"persistUsers":             boolValue(settings, "persistUsers"),
+		"allowUserToAccessProfile": boolValue(settings, "allowUserToAccessProfile"),
+		"providers":                providers,
+	})
+}
+
+func (s *Server) getSaml(w http.ResponseWriter, r *http.Request, _ string) {
+	writeJSON(w, http.StatusOK, s.descriptorSection("security", "samlSettings"))
+}
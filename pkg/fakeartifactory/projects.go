@@ -0,0 +1,235 @@
+package fakeartifactory
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// project is everything access keeps under access/api/v1/projects/{key}
+type project struct {
+	body   object
+	users  map[string]object
+	groups map[string]object
+	roles  map[string]object
+}
+
+// predefinedRoles come with every project, the same way access seeds them
+var predefinedRoles = []string{"Project Admin", "Developer", "Contributor", "Viewer", "Release Manager"}
+
+func (s *Server) registerProjects() {
+	// before the project routes, which would take _ for a project key
+	s.handle(http.MethodPut, "access/api/v1/projects/_/attach/repositories/", s.attachRepository)
+	s.handle(http.MethodDelete, "access/api/v1/projects/_/attach/repositories/", s.detachRepository)
+
+	s.handle(http.MethodPost, "access/api/v1/projects/", s.postProject)
+	s.handle(http.MethodGet, "access/api/v1/projects/", s.getProject)
+	s.handle(http.MethodPut, "access/api/v1/projects/", s.putProject)
+	s.handle(http.MethodDelete, "access/api/v1/projects/", s.deleteProject)
+}
+
+// projectPath splits key/kind/name, any of which may be empty
+func projectPath(rest string) (key, kind, name string) {
+	parts := strings.SplitN(rest, "/", 3)
+	key = parts[0]
+	if len(parts) > 1 {
+		kind = parts[1]
+	}
+	if len(parts) > 2 {
+		name = parts[2]
+	}
+	return
+}
+
+func (s *Server) findProject(w http.ResponseWriter, key string) (*project, bool) {
+	p, ok := s.projects[key]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Could not find project '%s'", key))
+	}
+	return p, ok
+}
+
+// collection is the users, groups or roles map of a project, or nil for anything else
+func (p *project) collection(kind string) map[string]object {
+	switch kind {
+	case "users":
+		return p.users
+	case "groups":
+		return p.groups
+	case "roles":
+		return p.roles
+	}
+	return nil
+}
+
+func (s *Server) postProject(w http.ResponseWriter, r *http.Request, rest string) {
+	body, err := readObject(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	key, kind, _ := projectPath(rest)
+	if key == "" {
+		key, _ = body["project_key"].(string)
+		if key == "" {
+			writeError(w, http.StatusBadRequest, "project_key is required")
+			return
+		}
+		if _, ok := s.projects[key]; ok {
+			writeError(w, http.StatusConflict, fmt.Sprintf("Project '%s' already exists", key))
+			return
+		}
+		p := &project{body: body, users: map[string]object{}, groups: map[string]object{}, roles: map[string]object{}}
+		for _, role := range predefinedRoles {
+			p.roles[role] = object{"name": role, "type": "PREDEFINED", "environments": []string{"DEV", "PROD"}, "actions": []string{}}
+		}
+		s.projects[key] = p
+		writeJSON(w, http.StatusCreated, body)
+		return
+	}
+
+	p, ok := s.findProject(w, key)
+	if !ok {
+		return
+	}
+	if kind != "roles" {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("no fake for %s %s", r.Method, r.URL.Path))
+		return
+	}
+	name, _ := body["name"].(string)
+	if _, ok := p.roles[name]; ok {
+		writeError(w, http.StatusConflict, fmt.Sprintf("Role '%s' already exists", name))
+		return
+	}
+	p.roles[name] = body
+	writeJSON(w, http.StatusCreated, body)
+}
+
+func (s *Server) getProject(w http.ResponseWriter, r *http.Request, rest string) {
+	key, kind, name := projectPath(rest)
+	p, ok := s.findProject(w, key)
+	if !ok {
+		return
+	}
+	if kind == "" {
+		writeJSON(w, http.StatusOK, p.body)
+		return
+	}
+	items := p.collection(kind)
+	if items == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("no fake for %s %s", r.Method, r.URL.Path))
+		return
+	}
+	if name != "" {
+		item, ok := items[name]
+		if !ok {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Could not find '%s' in project '%s'", name, key))
+			return
+		}
+		writeJSON(w, http.StatusOK, item)
+		return
+	}
+	list := []object{}
+	for _, n := range sortedKeys(items) {
+		list = append(list, items[n])
+	}
+	if kind == "roles" {
+		writeJSON(w, http.StatusOK, list)
+		return
+	}
+	writeJSON(w, http.StatusOK, object{"members": list})
+}
+
+func (s *Server) putProject(w http.ResponseWriter, r *http.Request, rest string) {
+	body, err := readObject(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	key, kind, name := projectPath(rest)
+	p, ok := s.findProject(w, key)
+	if !ok {
+		return
+	}
+	if kind == "" {
+		body["project_key"] = key
+		p.body = body
+		writeJSON(w, http.StatusOK, body)
+		return
+	}
+	items := p.collection(kind)
+	if items == nil || name == "" {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("no fake for %s %s", r.Method, r.URL.Path))
+		return
+	}
+	if kind == "roles" {
+		if _, ok := items[name]; !ok {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Could not find role '%s'", name))
+			return
+		}
+	} else {
+		for _, role := range stringList(body["roles"]) {
+			if _, ok := p.roles[role]; !ok {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("Role '%s' does not exist in project '%s'", role, key))
+				return
+			}
+		}
+	}
+	body["name"] = name
+	items[name] = body
+	writeJSON(w, http.StatusOK, body)
+}
+
+func (s *Server) deleteProject(w http.ResponseWriter, r *http.Request, rest string) {
+	key, kind, name := projectPath(rest)
+	p, ok := s.findProject(w, key)
+	if !ok {
+		return
+	}
+	if kind == "" {
+		delete(s.projects, key)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	items := p.collection(kind)
+	if _, ok := items[name]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Could not find '%s' in project '%s'", name, key))
+		return
+	}
+	delete(items, name)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// attachRepository handles {repo}/{project}. Without force a repository already in another project is refused
+func (s *Server) attachRepository(w http.ResponseWriter, r *http.Request, rest string) {
+	parts := strings.SplitN(rest, "/", 2)
+	if len(parts) != 2 {
+		writeError(w, http.StatusBadRequest, "expected {repo}/{project}")
+		return
+	}
+	repo, ok := s.repositories[parts[0]]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Repository '%s' not found", parts[0]))
+		return
+	}
+	if _, ok := s.findProject(w, parts[1]); !ok {
+		return
+	}
+	if current, _ := repo["projectKey"].(string); current != "" && current != parts[1] && r.URL.Query().Get("force") != "true" {
+		writeError(w, http.StatusConflict, fmt.Sprintf("Repository '%s' is already assigned to project '%s'", parts[0], current))
+		return
+	}
+	repo["projectKey"] = parts[1]
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) detachRepository(w http.ResponseWriter, r *http.Request, name string) {
+	repo, ok := s.repositories[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Repository '%s' not found", name))
+		return
+	}
+	delete(repo, "projectKey")
+	delete(repo, "environments")
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,106 @@
+package fakeartifactory
+
+import (
+	"fmt"
+	"net/http"
+)
+
+func (s *Server) registerReplications() {
+	s.handle(http.MethodPut, "artifactory/api/replications/multiple/", s.replaceMultipleReplications)
+	s.handle(http.MethodPost, "artifactory/api/replications/multiple/", s.replaceMultipleReplications)
+	s.handle(http.MethodGet, "artifactory/api/replications/", s.getReplications)
+	s.handle(http.MethodHead, "artifactory/api/replications/", s.getReplications)
+	s.handle(http.MethodPut, "artifactory/api/replications/", s.replaceReplication)
+	s.handle(http.MethodPost, "artifactory/api/replications/", s.updateReplication)
+	s.handle(http.MethodDelete, "artifactory/api/replications/", s.deleteReplications)
+}
+
+func (s *Server) requireRepository(w http.ResponseWriter, key string) bool {
+	if _, ok := s.repositories[key]; !ok {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Could not find repository '%s'", key))
+		return false
+	}
+	return true
+}
+
+// getReplications answers like artifactory does: a pull replication (remote repo) is a single object, push
+// replications (local repos) are always an array
+func (s *Server) getReplications(w http.ResponseWriter, r *http.Request, key string) {
+	replications, ok := s.replications[key]
+	if !ok || len(replications) == 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Could not find replication for repository '%s'", key))
+		return
+	}
+	if repo, ok := s.repositories[key]; ok && repo["rclass"] == "remote" {
+		writeJSON(w, http.StatusOK, replications[0])
+		return
+	}
+	writeJSON(w, http.StatusOK, replications)
+}
+
+func (s *Server) replaceReplication(w http.ResponseWriter, r *http.Request, key string) {
+	if !s.requireRepository(w, key) {
+		return
+	}
+	body, err := readObject(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	body["repoKey"] = key
+	s.replications[key] = []object{body}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) updateReplication(w http.ResponseWriter, r *http.Request, key string) {
+	replications, ok := s.replications[key]
+	if !ok || len(replications) == 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Could not find replication for repository '%s'", key))
+		return
+	}
+	body, err := readObject(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	body["repoKey"] = key
+	merge(replications[0], body)
+	w.WriteHeader(http.StatusOK)
+}
+
+// replaceMultipleReplications takes {cronExp, enableEventReplication, replications: [...]} and stores each
+// replication with the shared settings folded in, which is how they come back on a GET
+func (s *Server) replaceMultipleReplications(w http.ResponseWriter, r *http.Request, key string) {
+	if !s.requireRepository(w, key) {
+		return
+	}
+	body, err := readObject(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var replications []object
+	if list, ok := body["replications"].([]interface{}); ok {
+		for _, item := range list {
+			replication, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			replication["repoKey"] = key
+			replication["cronExp"] = body["cronExp"]
+			replication["enableEventReplication"] = body["enableEventReplication"]
+			replications = append(replications, replication)
+		}
+	}
+	s.replications[key] = replications
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) deleteReplications(w http.ResponseWriter, r *http.Request, key string) {
+	if _, ok := s.replications[key]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Could not find replication for repository '%s'", key))
+		return
+	}
+	delete(s.replications, key)
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,109 @@
+package fakeartifactory
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+func (s *Server) registerRepositories() {
+	s.handle(http.MethodGet, "artifactory/api/repositories/", s.getRepository)
+	s.handle(http.MethodHead, "artifactory/api/repositories/", s.getRepository)
+	s.handle(http.MethodPut, "artifactory/api/repositories/", s.createRepository)
+	s.handle(http.MethodPost, "artifactory/api/repositories/", s.updateRepository)
+	s.handle(http.MethodDelete, "artifactory/api/repositories/", s.deleteRepository)
+}
+
+func (s *Server) getRepository(w http.ResponseWriter, r *http.Request, key string) {
+	if key == "" {
+		s.listRepositories(w, r)
+		return
+	}
+	repo, ok := s.repositories[key]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Repository %s not found", key))
+		return
+	}
+	writeJSON(w, http.StatusOK, repo)
+}
+
+func (s *Server) listRepositories(w http.ResponseWriter, r *http.Request) {
+	repoType := r.URL.Query().Get("type")
+	keys := make([]string, 0, len(s.repositories))
+	for key := range s.repositories {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	list := []object{}
+	for _, key := range keys {
+		repo := s.repositories[key]
+		if repoType != "" && !strings.EqualFold(repoType, fmt.Sprint(repo["rclass"])) {
+			continue
+		}
+		list = append(list, object{
+			"key":         key,
+			"type":        strings.ToUpper(fmt.Sprint(repo["rclass"])),
+			"packageType": repo["packageType"],
+			"url":         fmt.Sprintf("%s/artifactory/%s", s.URL, key),
+		})
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) createRepository(w http.ResponseWriter, r *http.Request, key string) {
+	body, err := readObject(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	for existing := range s.repositories {
+		if strings.EqualFold(existing, key) {
+			writeError(w, http.StatusBadRequest, "Case insensitive repository key already exists")
+			return
+		}
+	}
+	body["key"] = key
+	if _, ok := body["rclass"]; !ok {
+		body["rclass"] = "local"
+	}
+	if _, ok := body["packageType"]; !ok {
+		body["packageType"] = "generic"
+	}
+	s.repositories[key] = body
+	_, _ = w.Write([]byte(fmt.Sprintf("Successfully created repository '%s'", key)))
+}
+
+func (s *Server) updateRepository(w http.ResponseWriter, r *http.Request, key string) {
+	repo, ok := s.repositories[key]
+	if !ok {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Repository %s does not exist", key))
+		return
+	}
+	body, err := readObject(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	// the key and class of a repository can't be changed
+	delete(body, "key")
+	delete(body, "rclass")
+	merge(repo, body)
+	_, _ = w.Write([]byte(fmt.Sprintf("Repository %s update successfully.", key)))
+}
+
+func (s *Server) deleteRepository(w http.ResponseWriter, r *http.Request, key string) {
+	if _, ok := s.repositories[key]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Repository %s not found", key))
+		return
+	}
+	delete(s.repositories, key)
+	delete(s.replications, key)
+	for path := range s.files {
+		if strings.HasPrefix(path, key+"/") {
+			delete(s.files, path)
+		}
+	}
+	_, _ = w.Write([]byte(fmt.Sprintf("Repository '%s' and all its content have been removed successfully.", key)))
+}
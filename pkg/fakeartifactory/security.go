@@ -0,0 +1,482 @@
+package fakeartifactory
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func (s *Server) registerSecurity() {
+	s.handle(http.MethodGet, "artifactory/api/security/users/", s.getUser)
+	s.handle(http.MethodHead, "artifactory/api/security/users/", s.getUser)
+	s.handle(http.MethodPut, "artifactory/api/security/users/", s.createUser)
+	s.handle(http.MethodPost, "artifactory/api/security/users/", s.updateUser)
+	s.handle(http.MethodDelete, "artifactory/api/security/users/", s.deleteUser)
+
+	s.handle(http.MethodGet, "artifactory/api/security/groups/", s.getGroup)
+	s.handle(http.MethodHead, "artifactory/api/security/groups/", s.getGroup)
+	s.handle(http.MethodPut, "artifactory/api/security/groups/", s.createGroup)
+	s.handle(http.MethodPost, "artifactory/api/security/groups/", s.updateGroup)
+	s.handle(http.MethodDelete, "artifactory/api/security/groups/", s.deleteGroup)
+
+	s.handle(http.MethodGet, "artifactory/api/v2/security/permissions/", s.getPermission)
+	s.handle(http.MethodHead, "artifactory/api/v2/security/permissions/", s.getPermission)
+	s.handle(http.MethodPost, "artifactory/api/v2/security/permissions/", s.createPermission)
+	s.handle(http.MethodPut, "artifactory/api/v2/security/permissions/", s.updatePermission)
+	s.handle(http.MethodDelete, "artifactory/api/v2/security/permissions/", s.deletePermission)
+
+	s.handle(http.MethodGet, "artifactory/api/security/apiKey", s.getApiKey)
+	s.handle(http.MethodPost, "artifactory/api/security/apiKey", s.createApiKey)
+	s.handle(http.MethodDelete, "artifactory/api/security/apiKey", s.deleteApiKey)
+
+	s.handle(http.MethodPost, "artifactory/api/security/token/revoke", s.revokeToken)
+	s.handle(http.MethodPost, "artifactory/api/security/token", s.createToken)
+	s.handle(http.MethodGet, "artifactory/api/security/token", s.listTokens)
+
+	s.handle(http.MethodGet, "artifactory/api/security/keypair/", s.getKeyPair)
+	s.handle(http.MethodPost, "artifactory/api/security/keypair/", s.createKeyPair)
+	s.handle(http.MethodDelete, "artifactory/api/security/keypair/", s.deleteKeyPair)
+
+	s.handle(http.MethodGet, "artifactory/api/system/security/certificates/", s.listCertificates)
+	s.handle(http.MethodPost, "artifactory/api/system/security/certificates/", s.addCertificate)
+	s.handle(http.MethodDelete, "artifactory/api/system/security/certificates/", s.deleteCertificate)
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request, name string) {
+	if name == "" {
+		list := []object{}
+		for _, n := range sortedKeys(s.users) {
+			list = append(list, object{"name": n, "uri": fmt.Sprintf("%s/artifactory/api/security/users/%s", s.URL, n), "realm": "internal"})
+		}
+		writeJSON(w, http.StatusOK, list)
+		return
+	}
+	user, ok := s.users[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("User '%s' does not exist", name))
+		return
+	}
+	result := copyObject(user)
+	// artifactory never hands the password back
+	delete(result, "password")
+	result["groups"] = s.groupsOf(name)
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) createUser(w http.ResponseWriter, r *http.Request, name string) {
+	body, err := readObject(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	body["name"] = name
+	if _, ok := body["realm"]; !ok {
+		body["realm"] = "internal"
+	}
+	s.setGroupsOf(name, body)
+	s.users[name] = body
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) updateUser(w http.ResponseWriter, r *http.Request, name string) {
+	user, ok := s.users[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("User '%s' does not exist", name))
+		return
+	}
+	body, err := readObject(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	delete(body, "name")
+	s.setGroupsOf(name, body)
+	merge(user, body)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) deleteUser(w http.ResponseWriter, r *http.Request, name string) {
+	if _, ok := s.users[name]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("User '%s' does not exist", name))
+		return
+	}
+	delete(s.users, name)
+	for _, members := range s.members {
+		delete(members, name)
+	}
+	_, _ = w.Write([]byte(fmt.Sprintf("User '%s' has been removed successfully.", name)))
+}
+
+// membership lives in one place (s.members), users and groups are just two views of it
+func (s *Server) groupsOf(user string) []string {
+	groups := []string{}
+	for group, members := range s.members {
+		if members[user] {
+			groups = append(groups, group)
+		}
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+func (s *Server) setGroupsOf(user string, body object) {
+	raw, ok := body["groups"]
+	delete(body, "groups")
+	if !ok {
+		return
+	}
+	for _, members := range s.members {
+		delete(members, user)
+	}
+	for _, group := range stringList(raw) {
+		if s.members[group] == nil {
+			s.members[group] = map[string]bool{}
+		}
+		s.members[group][user] = true
+	}
+}
+
+func (s *Server) setMembers(group string, body object) {
+	raw, ok := body["userNames"]
+	delete(body, "userNames")
+	if !ok {
+		return
+	}
+	members := map[string]bool{}
+	for _, user := range stringList(raw) {
+		members[user] = true
+	}
+	s.members[group] = members
+}
+
+func (s *Server) getGroup(w http.ResponseWriter, r *http.Request, name string) {
+	if name == "" {
+		list := []object{}
+		for _, n := range sortedKeys(s.groups) {
+			list = append(list, object{"name": n, "uri": fmt.Sprintf("%s/artifactory/api/security/groups/%s", s.URL, n)})
+		}
+		writeJSON(w, http.StatusOK, list)
+		return
+	}
+	group, ok := s.groups[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Group '%s' does not exist", name))
+		return
+	}
+	result := copyObject(group)
+	if r.URL.Query().Get("includeUsers") == "true" {
+		users := []string{}
+		for user := range s.members[name] {
+			users = append(users, user)
+		}
+		sort.Strings(users)
+		result["userNames"] = users
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) createGroup(w http.ResponseWriter, r *http.Request, name string) {
+	body, err := readObject(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	body["name"] = name
+	if _, ok := body["realm"]; !ok {
+		body["realm"] = "internal"
+	}
+	s.setMembers(name, body)
+	s.groups[name] = body
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) updateGroup(w http.ResponseWriter, r *http.Request, name string) {
+	group, ok := s.groups[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Group '%s' does not exist", name))
+		return
+	}
+	body, err := readObject(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	delete(body, "name")
+	s.setMembers(name, body)
+	merge(group, body)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) deleteGroup(w http.ResponseWriter, r *http.Request, name string) {
+	if _, ok := s.groups[name]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Group '%s' does not exist", name))
+		return
+	}
+	delete(s.groups, name)
+	delete(s.members, name)
+	_, _ = w.Write([]byte(fmt.Sprintf("Group '%s' has been removed successfully.", name)))
+}
+
+func (s *Server) getPermission(w http.ResponseWriter, r *http.Request, name string) {
+	if name == "" {
+		list := []object{}
+		for _, n := range sortedKeys(s.permissions) {
+			list = append(list, object{"name": n, "uri": fmt.Sprintf("%s/artifactory/api/v2/security/permissions/%s", s.URL, n)})
+		}
+		writeJSON(w, http.StatusOK, list)
+		return
+	}
+	permission, ok := s.permissions[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Permission target '%s' does not exist", name))
+		return
+	}
+	writeJSON(w, http.StatusOK, permission)
+}
+
+func (s *Server) createPermission(w http.ResponseWriter, r *http.Request, name string) {
+	if _, ok := s.permissions[name]; ok {
+		writeError(w, http.StatusConflict, fmt.Sprintf("Permission target '%s' already exists", name))
+		return
+	}
+	body, err := readObject(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	body["name"] = name
+	s.permissions[name] = body
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) updatePermission(w http.ResponseWriter, r *http.Request, name string) {
+	if _, ok := s.permissions[name]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Permission target '%s' does not exist", name))
+		return
+	}
+	body, err := readObject(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	// a v2 permission target update replaces the whole target
+	body["name"] = name
+	s.permissions[name] = body
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) deletePermission(w http.ResponseWriter, r *http.Request, name string) {
+	if _, ok := s.permissions[name]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Permission target '%s' does not exist", name))
+		return
+	}
+	delete(s.permissions, name)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) getApiKey(w http.ResponseWriter, r *http.Request, _ string) {
+	if s.apiKey == "" {
+		writeJSON(w, http.StatusOK, object{})
+		return
+	}
+	writeJSON(w, http.StatusOK, object{"apiKey": s.apiKey})
+}
+
+func (s *Server) createApiKey(w http.ResponseWriter, r *http.Request, _ string) {
+	if s.apiKey != "" {
+		writeError(w, http.StatusBadRequest, "Api key already exists")
+		return
+	}
+	s.apiKey = randomString(32)
+	writeJSON(w, http.StatusCreated, object{"apiKey": s.apiKey})
+}
+
+func (s *Server) deleteApiKey(w http.ResponseWriter, r *http.Request, _ string) {
+	s.apiKey = ""
+	writeJSON(w, http.StatusOK, object{"info": "Api key removed"})
+}
+
+// createToken issues a JWT shaped token, whose jti is the token_id of the token listing. The signature is random
+func (s *Server) createToken(w http.ResponseWriter, r *http.Request, _ string) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	now := time.Now().Unix()
+	expiresIn, _ := strconv.ParseInt(r.PostForm.Get("expires_in"), 10, 64)
+	info := object{
+		"token_id":    randomString(32),
+		"issuer":      "jfrt@fake",
+		"subject":     "jfrt@fake/users/" + r.PostForm.Get("username"),
+		"issued_at":   now,
+		"expiry":      0,
+		"refreshable": r.PostForm.Get("refreshable") == "true",
+	}
+	if expiresIn > 0 {
+		info["expiry"] = now + expiresIn
+	}
+	claims, _ := json.Marshal(object{"jti": info["token_id"], "sub": info["subject"], "iss": info["issuer"], "iat": now, "exp": info["expiry"]})
+	token := object{
+		"access_token": strings.Join([]string{
+			base64.RawURLEncoding.EncodeToString([]byte(`{"typ":"JWT","alg":"RS256"}`)),
+			base64.RawURLEncoding.EncodeToString(claims),
+			randomString(64),
+		}, "."),
+		"expires_in": expiresIn,
+		"scope":      r.PostForm.Get("scope"),
+		"token_type": "Bearer",
+	}
+	if token["scope"] == "" {
+		token["scope"] = "member-of-groups:readers api:*"
+	}
+	if info["refreshable"] == true {
+		token["refresh_token"] = randomString(64)
+	}
+	s.tokens[token["access_token"].(string)] = info
+	writeJSON(w, http.StatusOK, token)
+}
+
+// listTokens lists what artifactory lists, the tokens that can be revoked, which are the ones that don't expire
+func (s *Server) listTokens(w http.ResponseWriter, r *http.Request, _ string) {
+	list := []object{}
+	for _, key := range sortedKeys(s.tokens) {
+		if info := s.tokens[key]; info["expiry"] == 0 {
+			list = append(list, info)
+		}
+	}
+	writeJSON(w, http.StatusOK, object{"tokens": list})
+}
+
+func (s *Server) revokeToken(w http.ResponseWriter, r *http.Request, _ string) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	token := r.PostForm.Get("token")
+	if _, ok := s.tokens[token]; !ok {
+		writeError(w, http.StatusNotFound, "Token not found")
+		return
+	}
+	delete(s.tokens, token)
+	_, _ = w.Write([]byte("Token revoked"))
+}
+
+func (s *Server) getKeyPair(w http.ResponseWriter, r *http.Request, name string) {
+	keypair, ok := s.keypairs[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Keypair '%s' not found", name))
+		return
+	}
+	result := copyObject(keypair)
+	delete(result, "privateKey")
+	delete(result, "passphrase")
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) createKeyPair(w http.ResponseWriter, r *http.Request, _ string) {
+	body, err := readObject(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	name, _ := body["pairName"].(string)
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "pairName is required")
+		return
+	}
+	if _, ok := s.keypairs[name]; ok {
+		writeError(w, http.StatusConflict, fmt.Sprintf("Keypair '%s' already exists", name))
+		return
+	}
+	body["unavailable"] = false
+	s.keypairs[name] = body
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) deleteKeyPair(w http.ResponseWriter, r *http.Request, name string) {
+	if _, ok := s.keypairs[name]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Keypair '%s' not found", name))
+		return
+	}
+	delete(s.keypairs, name)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listCertificates(w http.ResponseWriter, r *http.Request, _ string) {
+	list := []object{}
+	for _, alias := range sortedKeys(s.certificates) {
+		list = append(list, s.certificates[alias])
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) addCertificate(w http.ResponseWriter, r *http.Request, alias string) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	block, _ := pem.Decode(body)
+	if block == nil {
+		writeError(w, http.StatusBadRequest, "Invalid pem data")
+		return
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	fingerprint := sha256.Sum256(cert.Raw)
+	s.certificates[alias] = object{
+		"certificateAlias": alias,
+		"issuedTo":         cert.Subject.CommonName,
+		"issuedby":         cert.Issuer.CommonName,
+		"issuedOn":         cert.NotBefore.Format(time.RFC1123Z),
+		"validUntil":       cert.NotAfter.Format(time.RFC1123Z),
+		"fingerPrint":      formatFingerprint(fingerprint[:]),
+	}
+	writeJSON(w, http.StatusOK, object{"status": http.StatusOK, "message": "The certificates were successfully added"})
+}
+
+func (s *Server) deleteCertificate(w http.ResponseWriter, r *http.Request, alias string) {
+	if _, ok := s.certificates[alias]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Certificate '%s' not found", alias))
+		return
+	}
+	delete(s.certificates, alias)
+	writeJSON(w, http.StatusOK, object{"status": http.StatusOK, "message": "The certificates were successfully deleted"})
+}
+
+func formatFingerprint(f []byte) string {
+	parts := make([]string, len(f))
+	for i, b := range f {
+		parts[i] = hex.EncodeToString([]byte{b})
+	}
+	return strings.ToUpper(strings.Join(parts, ":"))
+}
+
+func randomString(length int) string {
+	b := make([]byte, length/2)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func sortedKeys(m map[string]object) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,201 @@
+// Package fakeartifactory is an in-memory stand-in for the parts of the Artifactory and Xray REST APIs the provider
+// talks to. It is not a simulation of artifactory - it stores what it is sent and hands it back, with just enough
+// of artifactory's quirks (status codes, error bodies, derived fields) for the acceptance tests to pass against it.
+//
+// Start one with NewServer and point ARTIFACTORY_URL at its URL.
+package fakeartifactory
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+const (
+	Version     = "7.27.10"
+	LicenseType = "Enterprise Plus"
+)
+
+type object = map[string]interface{}
+
+type handlerFunc func(w http.ResponseWriter, r *http.Request, rest string)
+
+type route struct {
+	method  string
+	prefix  string
+	handler handlerFunc
+}
+
+// Server holds everything in maps guarded by a single lock. Every handler takes the lock for its whole duration,
+// so the fake is strictly serial - which is also how artifactory treats the config descriptor.
+type Server struct {
+	*httptest.Server
+
+	mu           sync.Mutex
+	routes       []route
+	repositories map[string]object
+	users        map[string]object
+	groups       map[string]object
+	members      map[string]map[string]bool // group -> users
+	permissions  map[string]object
+	replications map[string][]object
+	keypairs     map[string]object
+	certificates map[string]object
+	apiKey       string
+	tokens       map[string]object
+	descriptor   object
+	files        map[string]*storedFile
+	policies     map[string]object
+	watches      map[string]object
+	projects     map[string]*project
+	accessTokens map[string]object
+	// refreshTokens maps a refresh token to the token it refreshes and the request that created it
+	refreshTokens map[string]object
+}
+
+// NewServer starts a fake on a random local port. Close it when done
+func NewServer() *Server {
+	s := &Server{
+		repositories:  map[string]object{},
+		users:         map[string]object{},
+		groups:        map[string]object{},
+		members:       map[string]map[string]bool{},
+		permissions:   map[string]object{},
+		replications:  map[string][]object{},
+		keypairs:      map[string]object{},
+		certificates:  map[string]object{},
+		tokens:        map[string]object{},
+		descriptor:    object{},
+		files:         map[string]*storedFile{},
+		policies:      map[string]object{},
+		watches:       map[string]object{},
+		projects:      map[string]*project{},
+		accessTokens:  map[string]object{},
+		refreshTokens: map[string]object{},
+	}
+	s.registerSystem()
+	s.registerRepositories()
+	s.registerSecurity()
+	s.registerReplications()
+	s.registerConfiguration()
+	s.registerXray()
+	s.registerProjects()
+	s.registerAccess()
+	// storage is last, it claims everything else under artifactory/
+	s.registerStorage()
+
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+func (s *Server) handle(method, prefix string, handler handlerFunc) {
+	s.routes = append(s.routes, route{method: method, prefix: prefix, handler: handler})
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(r.URL.Path, "/")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, rt := range s.routes {
+		if rt.method != r.Method {
+			continue
+		}
+		if path == strings.TrimSuffix(rt.prefix, "/") || strings.HasPrefix(path, rt.prefix) {
+			rest := strings.TrimPrefix(strings.TrimPrefix(path, strings.TrimSuffix(rt.prefix, "/")), "/")
+			rt.handler(w, r, rest)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Sprintf("no fake for %s %s", r.Method, r.URL.Path))
+}
+
+func (s *Server) registerSystem() {
+	s.handle(http.MethodGet, "artifactory/api/system/version", func(w http.ResponseWriter, r *http.Request, _ string) {
+		writeJSON(w, http.StatusOK, object{
+			"version":  Version,
+			"revision": "72710900",
+			"addons":   []string{"build", "docker", "ha", "replication", "xray"},
+			"license":  "fake",
+		})
+	})
+	s.handle(http.MethodGet, "artifactory/api/system/licenses", func(w http.ResponseWriter, r *http.Request, _ string) {
+		writeJSON(w, http.StatusOK, object{
+			"type":         LicenseType,
+			"validThrough": "Jan 1, 2099",
+			"licensedTo":   "fakeartifactory",
+		})
+	})
+	s.handle(http.MethodGet, "artifactory/api/system/ping", func(w http.ResponseWriter, r *http.Request, _ string) {
+		_, _ = w.Write([]byte("OK"))
+	})
+	s.handle(http.MethodPost, "artifactory/api/system/usage", func(w http.ResponseWriter, r *http.Request, _ string) {
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// writeError writes the {"errors":[{"status":..,"message":..}]} body artifactory and access use
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, object{
+		"errors": []object{{"status": status, "message": message}},
+	})
+}
+
+// writeXrayError writes the {"error":".."} body xray uses
+func writeXrayError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, object{"error": message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func readObject(r *http.Request) (object, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	result := object{}
+	if len(body) == 0 {
+		return result, nil
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// merge is how artifactory applies POST updates: the fields sent replace the ones stored, the rest stay
+func merge(into, from object) object {
+	for k, v := range from {
+		into[k] = v
+	}
+	return into
+}
+
+func copyObject(o object) object {
+	result := object{}
+	for k, v := range o {
+		result[k] = v
+	}
+	return result
+}
+
+func stringList(value interface{}) []string {
+	var result []string
+	if list, ok := value.([]interface{}); ok {
+		for _, item := range list {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+	}
+	return result
+}
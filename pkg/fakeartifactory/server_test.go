@@ -0,0 +1,139 @@
+package fakeartifactory
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func do(t *testing.T, s *Server, method, path, contentType, body string) (int, string) {
+	req, err := http.NewRequest(method, s.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	content, _ := ioutil.ReadAll(resp.Body)
+	return resp.StatusCode, string(content)
+}
+
+func TestRepositoryLifecycle(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	if status, _ := do(t, s, http.MethodPut, "/artifactory/api/repositories/foo", "application/json",
+		`{"rclass":"local","packageType":"npm","description":"before"}`); status != http.StatusOK {
+		t.Fatalf("create returned %d", status)
+	}
+	if status, body := do(t, s, http.MethodPut, "/artifactory/api/repositories/FOO", "application/json", `{}`); status != http.StatusBadRequest ||
+		!strings.Contains(body, "already exists") {
+		t.Errorf("expected a case insensitive conflict, got %d %s", status, body)
+	}
+	if status, _ := do(t, s, http.MethodPost, "/artifactory/api/repositories/foo", "application/json",
+		`{"description":"after","rclass":"remote"}`); status != http.StatusOK {
+		t.Fatalf("update returned %d", status)
+	}
+
+	_, body := do(t, s, http.MethodGet, "/artifactory/api/repositories/foo", "", "")
+	repo := object{}
+	if err := json.Unmarshal([]byte(body), &repo); err != nil {
+		t.Fatal(err)
+	}
+	if repo["description"] != "after" || repo["rclass"] != "local" || repo["packageType"] != "npm" {
+		t.Errorf("unexpected repository after update: %v", repo)
+	}
+
+	if status, _ := do(t, s, http.MethodDelete, "/artifactory/api/repositories/foo", "", ""); status != http.StatusOK {
+		t.Fatalf("delete returned %d", status)
+	}
+	if status, body := do(t, s, http.MethodGet, "/artifactory/api/repositories/foo", "", ""); status != http.StatusNotFound ||
+		!strings.Contains(body, `"errors"`) {
+		t.Errorf("expected an artifactory 404 after delete, got %d %s", status, body)
+	}
+}
+
+func TestConfigurationPatchMerges(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	patches := []string{
+		"security:\n  anonAccessEnabled: true\n  oauthSettings:\n    enableIntegration: true\n    oauthProvidersSettings:\n      github:\n        enabled: true\n        providerType: github\n",
+		"security:\n  oauthSettings:\n    oauthProvidersSettings:\n      gitlab:\n        enabled: false\n        providerType: gitlab\n      github: ~\n",
+	}
+	for _, patch := range patches {
+		if status, body := do(t, s, http.MethodPatch, "/artifactory/api/system/configuration", "application/yaml", patch); status != http.StatusOK {
+			t.Fatalf("patch returned %d %s", status, body)
+		}
+	}
+
+	_, body := do(t, s, http.MethodGet, "/artifactory/api/securityconfig", "", "")
+	if !strings.Contains(body, `"anonAccessEnabled":true`) {
+		t.Errorf("the first patch was lost: %s", body)
+	}
+
+	_, body = do(t, s, http.MethodGet, "/artifactory/api/oauth", "", "")
+	oauth := struct {
+		Enabled   bool `json:"enabled"`
+		Providers []struct {
+			Name string `json:"name"`
+		} `json:"providers"`
+	}{}
+	if err := json.Unmarshal([]byte(body), &oauth); err != nil {
+		t.Fatal(err)
+	}
+	if !oauth.Enabled || len(oauth.Providers) != 1 || oauth.Providers[0].Name != "gitlab" {
+		t.Errorf("expected only the gitlab provider to be left, got %s", body)
+	}
+}
+
+func TestStorageFileInfo(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	if status, body := do(t, s, http.MethodPut, "/artifactory/generic-local/a/b.txt", "text/plain", "hello"); status != http.StatusBadRequest {
+		t.Errorf("deploying to a missing repository should fail, got %d %s", status, body)
+	}
+	do(t, s, http.MethodPut, "/artifactory/api/repositories/generic-local", "application/json", `{}`)
+	if status, body := do(t, s, http.MethodPut, "/artifactory/generic-local/a/b.txt", "text/plain", "hello"); status != http.StatusCreated {
+		t.Fatalf("deploy returned %d %s", status, body)
+	}
+
+	_, body := do(t, s, http.MethodGet, "/artifactory/api/storage/generic-local/a/b.txt", "", "")
+	info := struct {
+		DownloadUri string `json:"downloadUri"`
+		Size        string `json:"size"`
+		Checksums   struct {
+			Sha256 string `json:"sha256"`
+		} `json:"checksums"`
+	}{}
+	if err := json.Unmarshal([]byte(body), &info); err != nil {
+		t.Fatal(err)
+	}
+	if info.Size != "5" || info.Checksums.Sha256 != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+		t.Errorf("unexpected file info %s", body)
+	}
+	if info.DownloadUri != s.URL+"/artifactory/generic-local/a/b.txt" {
+		t.Errorf("unexpected download uri %s", info.DownloadUri)
+	}
+	if _, content := do(t, s, http.MethodGet, "/artifactory/generic-local/a/b.txt", "", ""); content != "hello" {
+		t.Errorf("download returned %q", content)
+	}
+}
+
+func TestUnknownRoutesAreNotFound(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	if status, body := do(t, s, http.MethodPost, "/artifactory/api/some/new/thing", "", ""); status != http.StatusNotFound ||
+		!strings.Contains(body, "no fake for") {
+		t.Errorf("expected a 404 naming the missing route, got %d %s", status, body)
+	}
+}
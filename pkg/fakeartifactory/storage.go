@@ -0,0 +1,126 @@
+package fakeartifactory
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type storedFile struct {
+	content  []byte
+	mimeType string
+	created  time.Time
+}
+
+func (s *Server) registerStorage() {
+	s.handle(http.MethodGet, "artifactory/api/storage/", s.getFileInfo)
+	s.handle(http.MethodPut, "artifactory/", s.deployFile)
+	s.handle(http.MethodGet, "artifactory/", s.downloadFile)
+	s.handle(http.MethodDelete, "artifactory/", s.deleteFile)
+}
+
+// PutFile deploys content without going through http, for tests that need an artifact to already be there
+func (s *Server) PutFile(repo, path string, content []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[repo+"/"+strings.TrimPrefix(path, "/")] = &storedFile{
+		content:  content,
+		mimeType: "application/octet-stream",
+		created:  time.Now().UTC(),
+	}
+}
+
+func splitRepoPath(rest string) (string, string) {
+	parts := strings.SplitN(rest, "/", 2)
+	if len(parts) < 2 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+func (s *Server) deployFile(w http.ResponseWriter, r *http.Request, rest string) {
+	repo, path := splitRepoPath(rest)
+	if !s.requireRepository(w, repo) {
+		return
+	}
+	if path == "" {
+		writeError(w, http.StatusBadRequest, "A path is required to deploy a file")
+		return
+	}
+	content, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	mimeType := r.Header.Get("Content-Type")
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	file := &storedFile{content: content, mimeType: mimeType, created: time.Now().UTC()}
+	s.files[rest] = file
+	writeJSON(w, http.StatusCreated, s.fileInfo(repo, path, file))
+}
+
+func (s *Server) fileInfo(repo, path string, file *storedFile) object {
+	md5sum := md5.Sum(file.content)
+	sha1sum := sha1.Sum(file.content)
+	sha256sum := sha256.Sum256(file.content)
+	checksums := object{
+		"md5":    hex.EncodeToString(md5sum[:]),
+		"sha1":   hex.EncodeToString(sha1sum[:]),
+		"sha256": hex.EncodeToString(sha256sum[:]),
+	}
+	created := file.created.Format("2006-01-02T15:04:05.000Z")
+	return object{
+		"repo":              repo,
+		"path":              "/" + path,
+		"created":           created,
+		"createdBy":         "admin",
+		"lastModified":      created,
+		"modifiedBy":        "admin",
+		"lastUpdated":       created,
+		"downloadUri":       fmt.Sprintf("%s/artifactory/%s/%s", s.URL, repo, path),
+		"mimeType":          file.mimeType,
+		"size":              strconv.Itoa(len(file.content)),
+		"checksums":         checksums,
+		"originalChecksums": checksums,
+		"uri":               fmt.Sprintf("%s/artifactory/api/storage/%s/%s", s.URL, repo, path),
+	}
+}
+
+func (s *Server) getFileInfo(w http.ResponseWriter, r *http.Request, rest string) {
+	file, ok := s.files[rest]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Unable to find item")
+		return
+	}
+	repo, path := splitRepoPath(rest)
+	writeJSON(w, http.StatusOK, s.fileInfo(repo, path, file))
+}
+
+func (s *Server) downloadFile(w http.ResponseWriter, r *http.Request, rest string) {
+	file, ok := s.files[rest]
+	if !ok {
+		writeError(w, http.StatusNotFound, "File not found.")
+		return
+	}
+	w.Header().Set("Content-Type", file.mimeType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(file.content)))
+	_, _ = w.Write(file.content)
+}
+
+func (s *Server) deleteFile(w http.ResponseWriter, r *http.Request, rest string) {
+	if _, ok := s.files[rest]; !ok {
+		writeError(w, http.StatusNotFound, "Could not locate artifact")
+		return
+	}
+	delete(s.files, rest)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,141 @@
+package fakeartifactory
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+func (s *Server) registerXray() {
+	s.handle(http.MethodPost, "xray/api/v1/policies", s.createPolicy)
+	s.handle(http.MethodGet, "xray/api/v1/policies/", s.getPolicy)
+	s.handle(http.MethodPut, "xray/api/v1/policies/", s.updatePolicy)
+	s.handle(http.MethodDelete, "xray/api/v1/policies/", s.deletePolicy)
+
+	s.handle(http.MethodPost, "xray/api/v2/watches", s.createWatch)
+	s.handle(http.MethodGet, "xray/api/v2/watches/", s.getWatch)
+	s.handle(http.MethodPut, "xray/api/v2/watches/", s.updateWatch)
+	s.handle(http.MethodDelete, "xray/api/v2/watches/", s.deleteWatch)
+}
+
+func xrayTimestamp() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+func (s *Server) createPolicy(w http.ResponseWriter, r *http.Request, _ string) {
+	body, err := readObject(r)
+	if err != nil {
+		writeXrayError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	name, _ := body["name"].(string)
+	if name == "" {
+		writeXrayError(w, http.StatusBadRequest, "Policy name is required")
+		return
+	}
+	if _, ok := s.policies[name]; ok {
+		writeXrayError(w, http.StatusConflict, fmt.Sprintf("Policy %s already exists", name))
+		return
+	}
+	// xray fills these in itself and the provider reads them back
+	now := xrayTimestamp()
+	body["author"] = "admin"
+	body["created"] = now
+	body["modified"] = now
+	s.policies[name] = body
+	writeJSON(w, http.StatusCreated, object{"info": "Policy created successfully"})
+}
+
+func (s *Server) getPolicy(w http.ResponseWriter, r *http.Request, name string) {
+	policy, ok := s.policies[name]
+	if !ok {
+		writeXrayError(w, http.StatusNotFound, "Failed to find Policy")
+		return
+	}
+	writeJSON(w, http.StatusOK, policy)
+}
+
+func (s *Server) updatePolicy(w http.ResponseWriter, r *http.Request, name string) {
+	policy, ok := s.policies[name]
+	if !ok {
+		writeXrayError(w, http.StatusNotFound, "Failed to find Policy")
+		return
+	}
+	body, err := readObject(r)
+	if err != nil {
+		writeXrayError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	body["name"] = name
+	body["author"] = policy["author"]
+	body["created"] = policy["created"]
+	body["modified"] = xrayTimestamp()
+	s.policies[name] = body
+	writeJSON(w, http.StatusOK, object{"info": "Policy updated successfully"})
+}
+
+func (s *Server) deletePolicy(w http.ResponseWriter, r *http.Request, name string) {
+	if _, ok := s.policies[name]; !ok {
+		writeXrayError(w, http.StatusNotFound, "Failed to find Policy")
+		return
+	}
+	delete(s.policies, name)
+	writeJSON(w, http.StatusOK, object{"info": "Policy deleted successfully"})
+}
+
+func watchName(watch object) string {
+	general, _ := watch["general_data"].(map[string]interface{})
+	name, _ := general["name"].(string)
+	return name
+}
+
+func (s *Server) createWatch(w http.ResponseWriter, r *http.Request, _ string) {
+	body, err := readObject(r)
+	if err != nil {
+		writeXrayError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	name := watchName(body)
+	if name == "" {
+		writeXrayError(w, http.StatusBadRequest, "Watch name is required")
+		return
+	}
+	if _, ok := s.watches[name]; ok {
+		writeXrayError(w, http.StatusConflict, fmt.Sprintf("Watch %s already exists", name))
+		return
+	}
+	s.watches[name] = body
+	writeJSON(w, http.StatusCreated, object{"info": "Watch has been successfully created"})
+}
+
+func (s *Server) getWatch(w http.ResponseWriter, r *http.Request, name string) {
+	watch, ok := s.watches[name]
+	if !ok {
+		writeXrayError(w, http.StatusNotFound, "Watch not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, watch)
+}
+
+func (s *Server) updateWatch(w http.ResponseWriter, r *http.Request, name string) {
+	if _, ok := s.watches[name]; !ok {
+		writeXrayError(w, http.StatusNotFound, "Watch not found")
+		return
+	}
+	body, err := readObject(r)
+	if err != nil {
+		writeXrayError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.watches[name] = body
+	writeJSON(w, http.StatusOK, object{"info": "Watch was successfully updated"})
+}
+
+func (s *Server) deleteWatch(w http.ResponseWriter, r *http.Request, name string) {
+	if _, ok := s.watches[name]; !ok {
+		writeXrayError(w, http.StatusNotFound, "Watch not found")
+		return
+	}
+	delete(s.watches, name)
+	writeJSON(w, http.StatusOK, object{"info": "Watch has been successfully deleted"})
+}